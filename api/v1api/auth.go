@@ -1,30 +1,40 @@
 package v1api
 
 import (
-	"gojet/models"
+	"net/http"
+
 	"gojet/service"
-	"gojet/util/apperror"
+	"gojet/util/binding"
 	"gojet/util/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+// cookieName 存放 token 的 httpOnly cookie 名称，由 InitAuth 在启动时设置
+var cookieName string
+
+// InitAuth 设置登录接口下发 token 使用的 cookie 名称，为空时不支持 cookie 下发
+func InitAuth(name string) {
+	cookieName = name
+}
+
 // Login
 // @Summary 	用户登录
-// @Description 系统用户登录
+// @Description 系统用户登录，username 字段可填写用户名或邮箱
 // @Id 			Login
 // @Tags 		auth
-// @Param 		m 		body 		service.LoginReq true "账号密码信息"
+// @Param 		m 		body 		service.LoginReq true "账号（用户名或邮箱）密码信息"
+// @Param 		set_cookie 	query 	bool false "为 true 时通过 httpOnly cookie 下发 token"
 // @Success		200		{object}	response.Response{data=service.LoginResp}	"登录后token信息"
-// @Failure 	400 	{object} 	response.Response "请求参数无效"
-// @Failure 	401 	{object} 	response.Response "认证失败"
-// @Failure 	404 	{object} 	response.Response "用户不存在"
+// @Failure 	400 	{object} 	response.Response "请求参数无效或包含未知字段"
+// @Failure 	401 	{object} 	response.Response "用户不存在或密码错误"
+// @Failure 	403 	{object} 	response.Response "账号已被停用"
 // @Failure 	500 	{object} 	response.Response "服务器内部错误"
 // @Router /v1/login [post]
 func Login(ctx *gin.Context) {
 	var req service.LoginReq
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(ctx, apperror.InvalidParams)
+	if err := binding.StrictBind(ctx, &req); err != nil {
+		response.HandleError(ctx, err)
 		return
 	}
 
@@ -34,6 +44,12 @@ func Login(ctx *gin.Context) {
 		return
 	}
 
+	// 浏览器客户端可要求将 token 写入 httpOnly cookie
+	if ctx.Query("set_cookie") == "true" && cookieName != "" {
+		ctx.SetSameSite(http.SameSiteLaxMode)
+		ctx.SetCookie(cookieName, resp.AccessToken, int(resp.ExpiresIn), "/", "", ctx.Request.TLS != nil, true)
+	}
+
 	response.Success(ctx, "登录成功", resp)
 }
 
@@ -42,30 +58,26 @@ func Login(ctx *gin.Context) {
 // @Description 注册新用户
 // @Id 			Register
 // @Tags 		auth
-// @Param 		user 	body 		models.User true "用户信息"
+// @Param 		user 	body 		CreateUserRequest true "用户信息"
 // @Success		200		{object}	response.Response{data=models.User}	"注册成功的用户信息"
-// @Failure 	400 	{object} 	response.Response "请求参数无效"
+// @Failure 	400 	{object} 	response.Response "请求体格式错误或包含未知字段"
+// @Failure 	409 	{object} 	response.Response "用户名已存在或邮箱已被注册"
+// @Failure 	422 	{object} 	response.Response{data=[]binding.FieldError} "字段校验失败（例如邮箱格式不正确）"
 // @Failure 	500 	{object} 	response.Response "服务器内部错误"
 // @Router /v1/register [post]
 func Register(ctx *gin.Context) {
-	var user models.User
-	if err := ctx.ShouldBindJSON(&user); err != nil {
-		response.BadRequest(ctx, apperror.InvalidParams)
-		return
-	}
-
-	// 对密码进行哈希处理
-	if user.Password != "" {
-		hashedPassword, err := models.HashPassword(user.Password)
-		if err != nil {
-			response.Error(ctx, 500, "密码加密失败")
+	var req CreateUserRequest
+	if err := binding.StrictBind(ctx, &req); err != nil {
+		if fields := binding.FieldErrors(&req, err); fields != nil {
+			response.ValidationFailed(ctx, fields)
 			return
 		}
-		user.Password = hashedPassword
+		response.HandleError(ctx, err)
+		return
 	}
 
-	// 创建用户
-	newUser, err := service.CreateUser(&user)
+	// 创建用户（密码由 service 层哈希）
+	newUser, err := service.CreateUser(ctx.Request.Context(), req.user())
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -73,3 +85,113 @@ func Register(ctx *gin.Context) {
 
 	response.Success(ctx, "注册成功", newUser)
 }
+
+// Refresh
+// @Summary 	刷新 token
+// @Description 使用 accessToken（允许已过期）和 refreshToken 换取新的 token，旧的 refreshToken 随即失效
+// @Id 			Refresh
+// @Tags 		auth
+// @Param 		m 		body 		service.RefreshReq true "accessToken 与 refreshToken"
+// @Param 		set_cookie 	query 	bool false "为 true 时通过 httpOnly cookie 下发新的 token"
+// @Success		200		{object}	response.Response{data=service.LoginResp}	"新的token信息"
+// @Failure 	400 	{object} 	response.Response "请求参数无效或包含未知字段"
+// @Failure 	401 	{object} 	response.Response "token 无效、已过期或已使用"
+// @Failure 	403 	{object} 	response.Response "账号已被停用"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router /v1/refresh [post]
+func Refresh(ctx *gin.Context) {
+	var req service.RefreshReq
+	if err := binding.StrictBind(ctx, &req); err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	resp, err := req.Refresh(ctx)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	// 登录时选择了 cookie 的浏览器客户端，刷新后同样更新 cookie
+	if ctx.Query("set_cookie") == "true" && cookieName != "" {
+		ctx.SetSameSite(http.SameSiteLaxMode)
+		ctx.SetCookie(cookieName, resp.AccessToken, int(resp.ExpiresIn), "/", "", ctx.Request.TLS != nil, true)
+	}
+
+	response.Success(ctx, "刷新成功", resp)
+}
+
+// RequestPasswordReset
+// @Summary 	申请密码重置
+// @Description 为邮箱对应的用户生成 30 分钟内有效的密码重置令牌；邮箱未注册时同样返回成功
+// @Id 			RequestPasswordReset
+// @Tags 		auth
+// @Param 		m 		body 		service.PasswordResetRequestReq true "注册邮箱"
+// @Success		200		{object}	response.Response	"已受理"
+// @Failure 	400 	{object} 	response.Response "请求参数无效或包含未知字段"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router /v1/password-reset/request [post]
+func RequestPasswordReset(ctx *gin.Context) {
+	var req service.PasswordResetRequestReq
+	if err := binding.StrictBind(ctx, &req); err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	if err := req.Request(ctx); err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "如果该邮箱已注册，密码重置令牌已发送", nil)
+}
+
+// ConfirmPasswordReset
+// @Summary 	确认密码重置
+// @Description 使用密码重置令牌设置新密码，令牌只能使用一次，成功后该用户需要重新登录
+// @Id 			ConfirmPasswordReset
+// @Tags 		auth
+// @Param 		m 		body 		service.PasswordResetConfirmReq true "重置令牌与新密码"
+// @Success		200		{object}	response.Response	"密码重置成功"
+// @Failure 	400 	{object} 	response.Response "请求参数无效，或令牌无效、已过期或已使用"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router /v1/password-reset/confirm [post]
+func ConfirmPasswordReset(ctx *gin.Context) {
+	var req service.PasswordResetConfirmReq
+	if err := binding.StrictBind(ctx, &req); err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	if err := req.Confirm(ctx); err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "密码重置成功", nil)
+}
+
+// Logout
+// @Summary 	注销
+// @Description 吊销当前 accessToken，之后使用该 token 访问接口或刷新均返回 401；已下发的 cookie 同时清除
+// @Id 			Logout
+// @Tags 		auth
+// @Security 	BearerAuth
+// @Success		200		{object}	response.Response	"注销成功"
+// @Failure 	401 	{object} 	response.Response "令牌无效或已注销"
+// @Failure 	403 	{object} 	response.Response "令牌缺失或已过期"
+// @Failure 	503 	{object} 	response.Response "令牌黑名单暂不可用"
+// @Router /v1/logout [post]
+func Logout(ctx *gin.Context) {
+	if err := service.Logout(ctx.Request.Context(), ctx.GetString("jti"), ctx.GetTime("token_expires_at")); err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	if cookieName != "" {
+		ctx.SetSameSite(http.SameSiteLaxMode)
+		ctx.SetCookie(cookieName, "", -1, "/", "", ctx.Request.TLS != nil, true)
+	}
+
+	response.Success(ctx, "注销成功", nil)
+}
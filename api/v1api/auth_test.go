@@ -0,0 +1,116 @@
+package v1api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"gojet/models"
+	"gojet/service"
+	"gojet/util/apperror"
+	"gojet/util/response"
+)
+
+func TestLogin(t *testing.T) {
+	hash, err := models.HashPassword("secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		body any
+		want int
+	}{
+		{"登录成功", map[string]string{"username": "alice", "password": "secret123"}, http.StatusOK},
+		{"使用邮箱登录", map[string]string{"username": "alice@example.com", "password": "secret123"}, http.StatusOK},
+		{"邮箱大小写不同", map[string]string{"username": "Alice@Example.COM", "password": "secret123"}, http.StatusOK},
+		{"密码错误", map[string]string{"username": "alice", "password": "wrong-password"}, http.StatusUnauthorized},
+		{"用户不存在", map[string]string{"username": "nobody", "password": "secret123"}, http.StatusUnauthorized},
+		{"缺少用户名", map[string]string{"password": "secret123"}, http.StatusBadRequest},
+		{"缺少密码", map[string]string{"username": "alice"}, http.StatusBadRequest},
+		{"请求体格式错误", `{"username":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := alice()
+			user.Password = hash
+			setupUsers(t, user)
+			r := newEngine()
+			r.POST("/v1/login", Login)
+
+			w := doJSON(r, http.MethodPost, "/v1/login", tt.body, "")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want == http.StatusOK {
+				var resp service.LoginResp
+				decodeData(t, w, &resp)
+				if resp.AccessToken == "" || resp.RefreshToken == "" {
+					t.Errorf("response missing tokens: %s", w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name string
+		body any
+		want int
+	}{
+		{"注册成功", map[string]string{"username": "bob", "nick_name": "Bob", "password": "secret123", "email": "bob@example.com"}, http.StatusOK},
+		{"包含未知字段", map[string]string{"username": "bob", "nick_name": "Bob", "password": "secret123", "email": "bob@example.com", "is_admin": "true"}, http.StatusBadRequest},
+		{"请求体格式错误", `{"username":`, http.StatusBadRequest},
+		{"缺少邮箱", map[string]string{"username": "bob", "nick_name": "Bob", "password": "secret123"}, http.StatusUnprocessableEntity},
+		{"用户名已存在", map[string]string{"username": "alice", "nick_name": "A", "password": "secret123", "email": "a2@example.com"}, http.StatusConflict},
+		{"邮箱已被注册", map[string]string{"username": "bob", "nick_name": "Bob", "password": "secret123", "email": "alice@example.com"}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupUsers(t, alice())
+			r := newEngine()
+			r.POST("/v1/register", Register)
+
+			w := doJSON(r, http.MethodPost, "/v1/register", tt.body, "")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want == http.StatusOK {
+				var user models.User
+				decodeData(t, w, &user)
+				if user.ID == 0 || user.Username != "bob" || user.Role != models.RoleUser {
+					t.Errorf("user = {ID: %d, Username: %q, Role: %q}, want new bob with role user", user.ID, user.Username, user.Role)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterSameUsernameTwice(t *testing.T) {
+	repo := setupUsers(t)
+	r := newEngine()
+	r.POST("/v1/register", Register)
+
+	first := doJSON(r, http.MethodPost, "/v1/register", map[string]string{"username": "bob", "nick_name": "Bob", "password": "secret123", "email": "bob@example.com"}, "")
+	if first.Code != http.StatusOK {
+		t.Fatalf("first register: status = %d, body: %s", first.Code, first.Body.String())
+	}
+	// 邮箱不同，只有用户名冲突
+	second := doJSON(r, http.MethodPost, "/v1/register", map[string]string{"username": "bob", "nick_name": "Bob2", "password": "secret123", "email": "bob2@example.com"}, "")
+	if second.Code != http.StatusConflict {
+		t.Fatalf("second register: status = %d, want 409, body: %s", second.Code, second.Body.String())
+	}
+	var resp response.Response
+	if err := json.Unmarshal(second.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Message != apperror.UserExists {
+		t.Errorf("message = %q, want %q", resp.Message, apperror.UserExists)
+	}
+	// 用户名预检查命中后不应再写入数据库
+	if n := repo.Calls("Create"); n != 1 {
+		t.Errorf("Create called %d times, want 1", n)
+	}
+}
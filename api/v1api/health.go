@@ -4,72 +4,128 @@ import (
 	"database/sql"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
 	"time"
 
-	"gojet/config"
 	"gojet/util/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+// 健康检查使用的应用信息与数据库连接，由 InitHealth 在启动时设置
+var (
+	appVersion        string
+	appStartTime      time.Time
+	healthDB          *sql.DB
+	poolWaitThreshold int64
+	// lastWaitCount 上一次就绪检查时连接池的累计等待次数
+	lastWaitCount atomic.Int64
+)
+
+// InitHealth 设置健康检查返回的应用版本、启动时间、检查的数据库连接，以及就绪检查的连接池等待阈值
+func InitHealth(version string, startTime time.Time, db *sql.DB, waitThreshold int64) {
+	appVersion = version
+	appStartTime = startTime
+	healthDB = db
+	poolWaitThreshold = waitThreshold
+	lastWaitCount.Store(0)
+}
+
+// HealthStatus 健康检查结果
 type HealthStatus struct {
 	Status    string   `json:"status"`
 	Timestamp string   `json:"timestamp"`
 	Version   string   `json:"version"`
+	Uptime    string   `json:"uptime"`
 	Database  DBStatus `json:"database"`
 }
 
+// DBStatus 数据库状态
 type DBStatus struct {
 	Status  string `json:"status"`
 	Message string `json:"message,omitempty"`
 }
 
-func HealthCheck(c *gin.Context) {
+// ReadinessStatus 就绪检查结果
+type ReadinessStatus struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
 
-	db, exists := c.Get("db")
-	if !exists {
-		slog.Error("数据库连接未配置在 gin context 中")
-		response.Error(c, http.StatusServiceUnavailable, "数据库连接未初始化")
+// HealthCheck
+// @Summary 	健康检查
+// @Description 检查服务及数据库连通性
+// @Id 			HealthCheck
+// @Tags 		health
+// @Success		200		{object}	response.Response{data=HealthStatus}	"服务健康"
+// @Failure 	503 	{object} 	response.Response "数据库不可用"
+// @Router 		/v1/health [get]
+func HealthCheck(c *gin.Context) {
+	if !pingDB(c) {
 		return
 	}
 
-	sqlDB, ok := db.(*sql.DB)
-	if !ok {
-		slog.Error("gin context 中的数据库连接类型错误")
-		response.Error(c, http.StatusServiceUnavailable, "数据库连接类型错误")
-		return
+	health := HealthStatus{
+		Status:    "healthy",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Version:   appVersion,
+		Uptime:    time.Since(appStartTime).Round(time.Second).String(),
+		Database: DBStatus{
+			Status: "healthy",
+		},
 	}
 
-	// 测试数据库连通性
-	if err := sqlDB.Ping(); err != nil {
-		slog.Error("数据库 Ping 失败", "error", err)
-		response.Error(c, http.StatusServiceUnavailable, "数据库连接失败")
-		return
-	}
+	response.Success(c, "", health)
+}
 
-	// 从 gin context 获取配置
-	cfg, exists := c.Get("config")
-	if !exists {
-		slog.Error("配置未设置")
-		response.Error(c, http.StatusInternalServerError, "配置未初始化")
+// ReadinessCheck
+// @Summary 	就绪检查
+// @Description 数据库可连通且连接池未过载时才接收流量，连接池压力过大时返回 degraded
+// @Id 			ReadinessCheck
+// @Tags 		health
+// @Success		200		{object}	response.Response{data=ReadinessStatus}	"实例就绪"
+// @Failure 	503 	{object} 	response.Response{data=ReadinessStatus} "数据库不可用或连接池压力过大"
+// @Router 		/v1/health/ready [get]
+func ReadinessCheck(c *gin.Context) {
+	if !pingDB(c) {
 		return
 	}
 
-	appConfig, ok := cfg.(*config.Config)
-	if !ok {
-		slog.Error("gin context 中的配置类型错误")
-		response.Error(c, http.StatusInternalServerError, "配置类型错误")
+	// Ping 成功不代表连接池还有空闲连接，两次检查之间等待次数过多时同样视为未就绪
+	if waits, degraded := poolPressure(healthDB.Stats()); degraded {
+		slog.Warn("连接池压力过大，实例未就绪", "waits", waits, "threshold", poolWaitThreshold)
+		c.JSON(http.StatusServiceUnavailable, response.Response{
+			Code:    http.StatusServiceUnavailable,
+			Message: "连接池压力过大",
+			Data: ReadinessStatus{
+				Status: "degraded",
+				Reason: "connection pool under pressure",
+			},
+		})
 		return
 	}
 
-	health := HealthStatus{
-		Status:    "healthy",
-		Timestamp: time.Now().Format(time.RFC3339),
-		Version:   appConfig.App.Version,
-		Database: DBStatus{
-			Status: "healthy",
-		},
+	response.Success(c, "", ReadinessStatus{Status: "ready"})
+}
+
+// pingDB 检查数据库连通性，失败时写入 503 并返回 false
+func pingDB(c *gin.Context) bool {
+	if healthDB == nil {
+		slog.Error("健康检查未设置数据库连接")
+		response.Error(c, http.StatusServiceUnavailable, "数据库连接未初始化")
+		return false
+	}
+	if err := healthDB.PingContext(c.Request.Context()); err != nil {
+		slog.Error("数据库 Ping 失败", "error", err)
+		response.Error(c, http.StatusServiceUnavailable, "数据库连接失败")
+		return false
 	}
+	return true
+}
 
-	response.Success(c, "", health)
+// poolPressure 返回自上一次就绪检查以来的连接池等待次数，以及是否达到阈值
+// WaitCount 是累计值，需与上一次检查比较，否则超过阈值后实例永远无法恢复就绪
+func poolPressure(stats sql.DBStats) (waits int64, degraded bool) {
+	waits = stats.WaitCount - lastWaitCount.Swap(stats.WaitCount)
+	return waits, waits >= poolWaitThreshold
 }
@@ -0,0 +1,90 @@
+package v1api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+)
+
+// newHealthEngine 注册健康检查路由，不需要任何上下文注入中间件
+func newHealthEngine() *gin.Engine {
+	r := gin.New()
+	r.GET("/v1/health", HealthCheck)
+	r.GET("/v1/health/ready", ReadinessCheck)
+	return r
+}
+
+func TestHealthCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		pingErr error
+		want    int
+	}{
+		{"数据库正常", nil, http.StatusOK},
+		{"数据库不可用", errors.New("connection refused"), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		for _, path := range []string{"/v1/health", "/v1/health/ready"} {
+			t.Run(tt.name+" "+path, func(t *testing.T) {
+				db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer db.Close()
+				mock.ExpectPing().WillReturnError(tt.pingErr)
+				InitHealth("1.2.3", time.Now().Add(-time.Minute), db, 10)
+
+				w := httptest.NewRecorder()
+				newHealthEngine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+				if w.Code != tt.want {
+					t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+				}
+				if path == "/v1/health" && tt.want == http.StatusOK {
+					var health HealthStatus
+					decodeData(t, w, &health)
+					if health.Version != "1.2.3" || health.Uptime != "1m0s" {
+						t.Errorf("health = {Version: %q, Uptime: %q}, want {1.2.3, 1m0s}", health.Version, health.Uptime)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestHealthCheckWithoutDB(t *testing.T) {
+	InitHealth("1.2.3", time.Now(), nil, 10)
+	w := httptest.NewRecorder()
+	newHealthEngine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+}
+
+func TestPoolPressure(t *testing.T) {
+	InitHealth("test", time.Now(), nil, 10)
+
+	tests := []struct {
+		waitCount    int64
+		wantWaits    int64
+		wantDegraded bool
+	}{
+		{3, 3, false},
+		{15, 12, true},
+		// 累计值仍高于阈值，但两次检查之间没有新增等待，应恢复就绪
+		{15, 0, false},
+		{20, 5, false},
+	}
+	for _, tt := range tests {
+		waits, degraded := poolPressure(sql.DBStats{WaitCount: tt.waitCount})
+		if waits != tt.wantWaits || degraded != tt.wantDegraded {
+			t.Errorf("poolPressure(WaitCount=%d) = (%d, %v), want (%d, %v)",
+				tt.waitCount, waits, degraded, tt.wantWaits, tt.wantDegraded)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package v1api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"gojet/config"
+	"gojet/models"
+	"gojet/service"
+	"gojet/service/servicetest"
+	"gojet/util/jwt"
+	"gojet/util/messaging"
+	"gojet/util/response"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testSecret 测试使用的 JWT 签名密钥
+const testSecret = "test-secret"
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	// 测试中使用最小 cost，避免 bcrypt 拖慢测试
+	if err := models.SetBCryptCost(bcrypt.MinCost); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// setupUsers 使用内存仓库初始化 service 层
+func setupUsers(t *testing.T, users ...*models.User) *servicetest.UserRepository {
+	t.Helper()
+	repo := servicetest.NewUserRepository(users...)
+	service.InitService(repo, messaging.NoopPublisher{})
+
+	cfg := config.DefaultConfig()
+	cfg.JWT.Secret = testSecret
+	refreshTokens := servicetest.NewRefreshTokenRepository()
+	service.InitAuth(cfg, refreshTokens, servicetest.NewPasswordResetTokenRepository(repo, refreshTokens), jwt.NewMemoryBlacklist())
+	return repo
+}
+
+// newEngine 创建带 JWT 校验的路由，login、register 不需要 token
+func newEngine() *gin.Engine {
+	r := gin.New()
+	r.Use(jwt.NewTokenMiddleware([]string{"login", "register"}, testSecret))
+	return r
+}
+
+// tokenFor 为用户签发 accessToken
+func tokenFor(t *testing.T, user *models.User) string {
+	t.Helper()
+	info, err := jwt.Sign(jwt.MapClaims{"id": user.ID, "username": user.Username, "role": user.Role}, testSecret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Token
+}
+
+// doJSON 发送 JSON 请求，body 为 nil 时不带请求体，token 为空时不带 Authorization 头
+func doJSON(r http.Handler, method, path string, body any, token string) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	switch b := body.(type) {
+	case nil:
+	case string:
+		// 原样发送，用于构造格式错误的请求体
+		buf.WriteString(b)
+	default:
+		if err := json.NewEncoder(&buf).Encode(b); err != nil {
+			panic(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decodeData 将统一响应中的 data 字段解析到 v
+func decodeData(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	resp := response.Response{Data: v}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v, body: %s", err, w.Body.String())
+	}
+}
@@ -0,0 +1,39 @@
+package v1api
+
+import (
+	"reflect"
+	"runtime"
+
+	"gojet/util/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteInfo 已注册路由信息
+type RouteInfo struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Handler string `json:"handler"`
+}
+
+// ListRoutes
+// @Summary 	列出所有已注册路由
+// @Description 仅在 debug 模式下注册，用于排查路由配置，不可用于生产环境
+// @Id 			ListRoutes
+// @Tags 		debug
+// @Success		200		{object}	response.Response{data=[]RouteInfo}	"路由列表"
+// @Router 		/v1/routes [get]
+func ListRoutes(r *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		routes := r.Routes()
+		infos := make([]RouteInfo, 0, len(routes))
+		for _, route := range routes {
+			infos = append(infos, RouteInfo{
+				Method:  route.Method,
+				Path:    route.Path,
+				Handler: runtime.FuncForPC(reflect.ValueOf(route.HandlerFunc).Pointer()).Name(),
+			})
+		}
+		response.Success(c, "", infos)
+	}
+}
@@ -1,9 +1,13 @@
 package v1api
 
 import (
+	"errors"
+	"net/http"
+
 	"gojet/models"
 	"gojet/service"
 	"gojet/util/apperror"
+	"gojet/util/binding"
 	"gojet/util/response"
 
 	"github.com/gin-gonic/gin"
@@ -11,13 +15,45 @@ import (
 
 // IDParam 用于绑定路径参数中的ID
 type IDParam struct {
-	ID int `uri:"id" binding:"required,min=1"`
+	ID uint `uri:"id" binding:"required,min=1"`
+}
+
+// PageQuery 分页查询参数，未传时使用默认值，传入 0 或负数时绑定失败
+type PageQuery struct {
+	Page int `form:"page,default=1" binding:"min=1"`
+	Size int `form:"size,default=20" binding:"min=1"`
+}
+
+// SearchQuery 用户搜索参数
+type SearchQuery struct {
+	Q     string `form:"q" binding:"required"`
+	Field string `form:"field,default=username" binding:"oneof=username nick_name email"`
 }
 
-// InsertInitialData 插入初始学生数据
+// bindError 处理请求体绑定失败，请求体超过大小限制时返回 413，其余返回 400
+func bindError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		response.PayloadTooLarge(c, apperror.PayloadTooLarge)
+		return
+	}
+	response.BadRequest(c, apperror.InvalidParams)
+}
+
+// InsertInitialData
+// @Summary 	插入初始数据
+// @Description 写入预置的初始用户数据，app.seed_enabled 为 false 时跳过
+// @Id 			InsertInitialData
+// @Tags 		auth
+// @Security 	BearerAuth
+// @Success		200		{object}	response.Response	"数据插入成功"
+// @Failure 	401 	{object} 	response.Response "认证失败"
+// @Failure 	403 	{object} 	response.Response "权限不足（需要管理员角色）"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router 		/v1/users/insert [post]
 func InsertInitialData(c *gin.Context) {
 	// 调用服务层创建初始数据
-	if err := service.CreateInitialData(); err != nil {
+	if err := service.CreateInitialData(c.Request.Context()); err != nil {
 		response.HandleError(c, err)
 		return
 	}
@@ -29,13 +65,15 @@ func InsertInitialData(c *gin.Context) {
 // @Description 根据 ID 删除系统用户
 // @Id 			DeleteUser
 // @Tags 		auth
+// @Security 	BearerAuth
 // @Param 		id 		path 		int true "用户ID"
-// @Success		200		{object}	response.Response{data=nil}	"删除成功"
+// @Success		204		"删除成功"
 // @Failure 	400 	{object} 	response.Response "请求参数无效"
 // @Failure 	401 	{object} 	response.Response "认证失败"
+// @Failure 	403 	{object} 	response.Response "权限不足（需要管理员角色）"
 // @Failure 	404 	{object} 	response.Response "用户不存在"
 // @Failure 	500 	{object} 	response.Response "服务器内部错误"
-// @Router 		/v1/user/{id} [delete]
+// @Router 		/v1/users/{id} [delete]
 func DeleteUser(c *gin.Context) {
 	var idParam IDParam
 	if err := c.ShouldBindUri(&idParam); err != nil {
@@ -43,11 +81,95 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := service.DeleteUser(uint(idParam.ID)); err != nil {
+	if err := service.DeleteUser(c.Request.Context(), idParam.ID); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// ActivateUser
+// @Summary 	启用用户
+// @Description 将停用的用户账号恢复为正常状态
+// @Id 			ActivateUser
+// @Tags 		auth
+// @Security 	BearerAuth
+// @Param 		id 		path 		int true "用户ID"
+// @Success		200		{object}	response.Response	"启用成功"
+// @Failure 	400 	{object} 	response.Response "请求参数无效"
+// @Failure 	401 	{object} 	response.Response "认证失败"
+// @Failure 	403 	{object} 	response.Response "权限不足（需要管理员角色）"
+// @Failure 	404 	{object} 	response.Response "用户不存在"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router 		/v1/users/{id}/activate [post]
+func ActivateUser(c *gin.Context) {
+	setUserStatus(c, models.StatusActive, "启用成功")
+}
+
+// DeactivateUser
+// @Summary 	停用用户
+// @Description 停用用户账号，停用后无法登录或刷新 token，已签发的 token 在过期前仍然有效
+// @Id 			DeactivateUser
+// @Tags 		auth
+// @Security 	BearerAuth
+// @Param 		id 		path 		int true "用户ID"
+// @Success		200		{object}	response.Response	"停用成功"
+// @Failure 	400 	{object} 	response.Response "请求参数无效"
+// @Failure 	401 	{object} 	response.Response "认证失败"
+// @Failure 	403 	{object} 	response.Response "权限不足（需要管理员角色）"
+// @Failure 	404 	{object} 	response.Response "用户不存在"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router 		/v1/users/{id}/deactivate [post]
+func DeactivateUser(c *gin.Context) {
+	setUserStatus(c, models.StatusInactive, "停用成功")
+}
+
+// setUserStatus 启用与停用接口的公共实现
+func setUserStatus(c *gin.Context, status, message string) {
+	var idParam IDParam
+	if err := c.ShouldBindUri(&idParam); err != nil {
+		response.BadRequest(c, apperror.InvalidUserID)
+		return
+	}
+
+	if err := service.SetUserStatus(c.Request.Context(), idParam.ID, status); err != nil {
 		response.HandleError(c, err)
 		return
 	}
-	response.Success(c, "删除成功", nil)
+	response.Success(c, message, nil)
+}
+
+// DeleteUsersRequest 批量删除用户请求结构体
+type DeleteUsersRequest struct {
+	IDs []uint `json:"ids" binding:"required"` // 待删除的用户ID，重复的ID只删除一次
+}
+
+// DeleteUsers
+// @Summary 	批量删除用户
+// @Description 根据 ID 列表批量删除系统用户，数量上限由 app.max_batch_delete 配置（默认 100）；不存在的 ID 不视为失败，在 not_found 中返回
+// @Id 			DeleteUsers
+// @Tags 		auth
+// @Security 	BearerAuth
+// @Param 		ids 	body 		DeleteUsersRequest true "待删除的用户ID"
+// @Success		200		{object}	response.Response{data=service.DeleteUsersResp}	"删除结果"
+// @Failure 	400 	{object} 	response.Response "ID 列表为空、包含无效 ID 或超过数量上限"
+// @Failure 	401 	{object} 	response.Response "认证失败"
+// @Failure 	403 	{object} 	response.Response "权限不足（需要管理员角色）"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router 		/v1/users [delete]
+func DeleteUsers(c *gin.Context) {
+	var req DeleteUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := service.DeleteUsers(c.Request.Context(), req.IDs)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+	response.Success(c, "删除成功", resp)
 }
 
 // GetUserByID
@@ -55,13 +177,17 @@ func DeleteUser(c *gin.Context) {
 // @Description 根据 ID 获取系统用户详情
 // @Id 			GetUserByID
 // @Tags 		auth
+// @Security 	BearerAuth
 // @Param 		id 		path 		int true "用户ID"
+// @Param 		If-None-Match 	header 	string false "上次响应的 ETag"
+// @Param 		If-Modified-Since 	header 	string false "上次响应的 Last-Modified"
 // @Success		200		{object}	response.Response{data=models.User}	"用户详情"
+// @Success		304		"用户信息未修改"
 // @Failure 	400 	{object} 	response.Response "请求参数无效"
 // @Failure 	401 	{object} 	response.Response "认证失败"
 // @Failure 	404 	{object} 	response.Response "用户不存在"
 // @Failure 	500 	{object} 	response.Response "服务器内部错误"
-// @Router 		/v1/user/{id} [get]
+// @Router 		/v1/users/{id} [get]
 func GetUserByID(c *gin.Context) {
 	var idParam IDParam
 	if err := c.ShouldBindUri(&idParam); err != nil {
@@ -69,26 +195,88 @@ func GetUserByID(c *gin.Context) {
 		return
 	}
 
-	user, err := service.GetUserByID(uint(idParam.ID))
+	user, err := service.GetUserByID(c.Request.Context(), idParam.ID)
 	if err != nil {
 		// 使用 HandleError 统一处理，支持 400/404/500 等错误码
 		response.HandleError(c, err)
 		return
 	}
+
+	if response.SetCacheHeaders(c, user.UpdatedAt, user.ETag()) {
+		return
+	}
 	response.Success(c, "", user)
 }
 
 // GetAllUsers
 // @Summary 	获取所有用户列表
-// @Description 获取系统中所有用户的详细信息
+// @Description 分页获取系统用户，可通过查询参数过滤；size 超过 200 时按 200 处理
 // @Id 			GetAllUsers
 // @Tags 		auth
-// @Success		200		{object}	response.Response{data=[]models.User}	"用户列表"
+// @Security 	BearerAuth
+// @Param 		page 			query 	int false "页码，从 1 开始" default(1)
+// @Param 		size 			query 	int false "每页条数" default(20)
+// @Param 		username 		query 	string false "用户名（模糊匹配）"
+// @Param 		nick_name 		query 	string false "昵称（模糊匹配）"
+// @Param 		email 			query 	string false "邮箱（忽略大小写）"
+// @Param 		status 			query 	string false "账号状态" Enums(active, inactive)
+// @Param 		created_after 	query 	string false "创建时间下限（RFC3339）"
+// @Param 		created_before 	query 	string false "创建时间上限（RFC3339）"
+// @Success		200		{object}	response.Response{data=response.PagedResponse[models.User]}	"用户列表"
+// @Failure 	400 	{object} 	response.Response "请求参数无效"
 // @Failure 	401 	{object} 	response.Response "认证失败"
+// @Failure 	429 	{object} 	response.Response "请求过于频繁"
 // @Failure 	500 	{object} 	response.Response "服务器内部错误"
 // @Router 		/v1/users [get]
 func GetAllUsers(c *gin.Context) {
-	users, err := service.GetAllUsers()
+	var page PageQuery
+	if err := c.ShouldBindQuery(&page); err != nil {
+		response.BadRequest(c, apperror.InvalidParams)
+		return
+	}
+	page.Size = min(page.Size, service.MaxPageSize)
+
+	var filter models.UserFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.BadRequest(c, apperror.InvalidParams)
+		return
+	}
+
+	users, total, err := service.GetFilteredUsers(c.Request.Context(), filter, page.Page, page.Size)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+	response.Success(c, "", response.PagedResponse[*models.User]{
+		Total: total,
+		Page:  page.Page,
+		Size:  page.Size,
+		Data:  users,
+	})
+}
+
+// SearchUsers
+// @Summary 	搜索用户
+// @Description 按用户名、昵称或邮箱模糊搜索用户（忽略大小写），最多返回 100 条
+// @Id 			SearchUsers
+// @Tags 		auth
+// @Security 	BearerAuth
+// @Param 		q 		query 	string true "搜索关键字"
+// @Param 		field 	query 	string false "搜索字段" Enums(username, nick_name, email) default(username)
+// @Success		200		{object}	response.Response{data=[]models.User}	"匹配的用户"
+// @Failure 	400 	{object} 	response.Response "关键字为空或搜索字段不支持"
+// @Failure 	401 	{object} 	response.Response "认证失败"
+// @Failure 	429 	{object} 	response.Response "请求过于频繁"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router 		/v1/users/search [get]
+func SearchUsers(c *gin.Context) {
+	var query SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, apperror.InvalidParams)
+		return
+	}
+
+	users, err := service.SearchUsers(c.Request.Context(), query.Q, query.Field)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -96,49 +284,145 @@ func GetAllUsers(c *gin.Context) {
 	response.Success(c, "", users)
 }
 
+// FindDuplicateUsers
+// @Summary 	查找邮箱重复的用户
+// @Description 按忽略大小写的邮箱分组，返回被多个用户使用的邮箱及对应用户ID（管理员接口）
+// @Id 			FindDuplicateUsers
+// @Tags 		admin
+// @Security 	BearerAuth
+// @Success		200		{object}	response.Response{data=[]models.DuplicateGroup}	"重复邮箱分组"
+// @Failure 	401 	{object} 	response.Response "认证失败"
+// @Failure 	403 	{object} 	response.Response "权限不足（需要管理员角色）"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router 		/v1/admin/users/duplicates [get]
+func FindDuplicateUsers(c *gin.Context) {
+	groups, err := service.FindDuplicates(c.Request.Context())
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+	response.Success(c, "", groups)
+}
+
+// CreateUserRequest 创建用户与注册请求结构体
+// models.User 的密码不参与 JSON 编解码，请求需要单独的结构体接收明文密码
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required"`    // 用户登录名称
+	NickName string `json:"nick_name" binding:"required"`   // 用户昵称（显示名称）
+	Password string `json:"password" binding:"required"`    // 登录密码（最长 72 字节）
+	Email    string `json:"email" binding:"required,email"` // 用户电子邮箱
+}
+
+// user 转换为待创建的用户，角色与状态由 service 层设置
+func (r *CreateUserRequest) user() *models.User {
+	return &models.User{
+		Username: r.Username,
+		NickName: r.NickName,
+		Password: r.Password,
+		Email:    r.Email,
+	}
+}
+
 // CreateUser
 // @Summary 	创建新用户
 // @Description 创建一个新的系统用户，从请求体获取用户信息
 // @Id 			CreateUser
 // @Tags 		auth
-// @Param 		user 	body 		models.User true "用户信息"
-// @Success		200		{object}	response.Response{data=models.User}	"创建成功"
+// @Security 	BearerAuth
+// @Param 		user 	body 		CreateUserRequest true "用户信息"
+// @Success		201		{object}	response.Response{data=models.User}	"创建成功"
 // @Failure 	400 	{object} 	response.Response "请求参数无效"
 // @Failure 	401 	{object} 	response.Response "认证失败"
+// @Failure 	409 	{object} 	response.Response "用户名已存在或邮箱已被注册"
+// @Failure 	413 	{object} 	response.Response "请求体过大"
+// @Failure 	422 	{object} 	response.Response{data=[]binding.FieldError} "字段校验失败（例如邮箱格式不正确）"
 // @Failure 	500 	{object} 	response.Response "服务器内部错误"
-// @Router 		/v1/user [post]
+// @Router 		/v1/users [post]
 func CreateUser(c *gin.Context) {
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		response.BadRequest(c, apperror.InvalidParams)
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if fields := binding.FieldErrors(&req, err); fields != nil {
+			response.ValidationFailed(c, fields)
+			return
+		}
+		bindError(c, err)
 		return
 	}
 
-	hashedPassword, err := models.HashPassword(user.Password)
+	newUser, err := service.CreateUser(c.Request.Context(), req.user())
 	if err != nil {
-		response.Error(c, 500, "密码加密失败")
+		response.HandleError(c, err)
 		return
 	}
-	user.Password = hashedPassword
+	response.Created(c, "创建成功", newUser)
+}
 
-	newUser, err := service.CreateUser(&user)
-	if err != nil {
+// ChangePasswordRequest 修改密码请求结构体
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"` // 原密码
+	NewPassword string `json:"new_password" binding:"required"` // 新密码，至少 8 位且包含数字（最长 72 字节）
+}
+
+// ChangePassword
+// @Summary 	修改密码
+// @Description 用户校验原密码后修改自己的密码，成功后该用户所有 refreshToken 失效
+// @Id 			ChangePassword
+// @Tags 		auth
+// @Security 	BearerAuth
+// @Param 		id 		path 		int true "用户ID（必须为当前登录用户）"
+// @Param 		m 		body 		ChangePasswordRequest true "原密码与新密码"
+// @Success		200		{object}	response.Response	"修改成功"
+// @Failure 	400 	{object} 	response.Response "请求参数无效或新密码强度不足"
+// @Failure 	401 	{object} 	response.Response "原密码错误"
+// @Failure 	403 	{object} 	response.Response "只能修改自己的密码"
+// @Failure 	404 	{object} 	response.Response "用户不存在"
+// @Failure 	500 	{object} 	response.Response "服务器内部错误"
+// @Router 		/v1/users/{id}/password [put]
+func ChangePassword(c *gin.Context) {
+	var idParam IDParam
+	if err := c.ShouldBindUri(&idParam); err != nil {
+		response.BadRequest(c, apperror.InvalidUserID)
+		return
+	}
+	// 管理员同样只能修改自己的密码，他人忘记密码时使用密码重置流程
+	if c.GetUint("userid") != idParam.ID {
+		response.Forbidden(c, apperror.PermissionDenied)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if err := service.ChangePassword(c.Request.Context(), idParam.ID, req.OldPassword, req.NewPassword); err != nil {
 		response.HandleError(c, err)
 		return
 	}
-	response.Success(c, "创建成功", newUser)
+	response.Success(c, "密码修改成功", nil)
 }
 
 // UpdateUserRequest 更新用户请求结构体
 type UpdateUserRequest struct {
-	Name string `json:"name" binding:"required"`
+	NickName string `json:"nick_name" binding:"required_without=Name"` // 新的用户昵称，与 name 至少提供一个
+	Name     string `json:"name"`                                      // 已废弃，等同于 nick_name，仅为兼容旧版客户端保留
+}
+
+// nickName 返回新的昵称，同时提供 nick_name 与 name 时以 nick_name 为准
+func (r *UpdateUserRequest) nickName() string {
+	if r.NickName != "" {
+		return r.NickName
+	}
+	return r.Name
 }
 
 // UpdateUser
 // @Summary 	更新用户信息
-// @Description 根据 ID 更新系统用户的姓名
+// @Description 根据 ID 更新系统用户的昵称（登录名不可修改）
 // @Id 			UpdateUser
 // @Tags 		auth
+// @Security 	BearerAuth
 // @Param 		id 		path 		int true "用户ID"
 // @Param 		user 	body 		UpdateUserRequest true "更新用户信息"
 // @Success		200		{object}	response.Response{data=models.User}	"更新成功"
@@ -146,7 +430,7 @@ type UpdateUserRequest struct {
 // @Failure 	401 	{object} 	response.Response "认证失败"
 // @Failure 	404 	{object} 	response.Response "用户不存在"
 // @Failure 	500 	{object} 	response.Response "服务器内部错误"
-// @Router 		/v1/user/{id} [put]
+// @Router 		/v1/users/{id} [put]
 func UpdateUser(c *gin.Context) {
 	var idParam IDParam
 	if err := c.ShouldBindUri(&idParam); err != nil {
@@ -156,11 +440,11 @@ func UpdateUser(c *gin.Context) {
 
 	var updateReq UpdateUserRequest
 	if err := c.ShouldBindJSON(&updateReq); err != nil {
-		response.BadRequest(c, apperror.InvalidParams)
+		bindError(c, err)
 		return
 	}
 
-	updatedUser, err := service.UpdateUser(uint(idParam.ID), updateReq.Name)
+	updatedUser, err := service.UpdateUser(c.Request.Context(), idParam.ID, updateReq.nickName())
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -0,0 +1,350 @@
+package v1api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"gojet/models"
+	"gojet/service"
+	"gojet/util/response"
+)
+
+// alice 测试使用的普通用户
+func alice() *models.User {
+	return &models.User{ID: 1, Username: "alice", NickName: "Alice", Email: "alice@example.com", Password: "hash", Role: models.RoleUser, Status: models.StatusActive}
+}
+
+func TestGetUserByID(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		token bool
+		want  int
+	}{
+		{"存在", "/v1/users/1", true, http.StatusOK},
+		{"不存在", "/v1/users/2", true, http.StatusNotFound},
+		{"ID 无效", "/v1/users/abc", true, http.StatusBadRequest},
+		{"缺少 token", "/v1/users/1", false, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := alice()
+			setupUsers(t, user)
+			r := newEngine()
+			r.GET("/v1/users/:id", GetUserByID)
+
+			var token string
+			if tt.token {
+				token = tokenFor(t, user)
+			}
+			w := doJSON(r, http.MethodGet, tt.path, nil, token)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want == http.StatusOK {
+				var got models.User
+				decodeData(t, w, &got)
+				if got.ID != 1 || got.Username != "alice" {
+					t.Errorf("user = {ID: %d, Username: %q}, want {1, alice}", got.ID, got.Username)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateUser(t *testing.T) {
+	tests := []struct {
+		name string
+		body any
+		want int
+	}{
+		{"创建成功", map[string]string{"username": "bob", "nick_name": "Bob", "password": "secret123", "email": "bob@example.com"}, http.StatusCreated},
+		{"缺少必填字段", map[string]string{"username": "bob"}, http.StatusUnprocessableEntity},
+		{"请求体格式错误", `{"username":`, http.StatusBadRequest},
+		{"用户名已存在", map[string]string{"username": "alice", "nick_name": "A", "password": "secret123", "email": "a2@example.com"}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := alice()
+			repo := setupUsers(t, user)
+			r := newEngine()
+			r.POST("/v1/users", CreateUser)
+
+			w := doJSON(r, http.MethodPost, "/v1/users", tt.body, tokenFor(t, user))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			wantUsers := 1
+			if tt.want == http.StatusCreated {
+				wantUsers = 2
+			}
+			if n := len(repo.Users()); n != wantUsers {
+				t.Errorf("repository has %d users, want %d", n, wantUsers)
+			}
+		})
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"更新成功", "/v1/users/1", http.StatusOK},
+		{"用户不存在", "/v1/users/2", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := alice()
+			setupUsers(t, user)
+			r := newEngine()
+			r.PUT("/v1/users/:id", UpdateUser)
+
+			w := doJSON(r, http.MethodPut, tt.path, map[string]string{"nick_name": "Alice L"}, tokenFor(t, user))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want == http.StatusOK {
+				var got models.User
+				decodeData(t, w, &got)
+				if got.NickName != "Alice L" {
+					t.Errorf("NickName = %q, want Alice L", got.NickName)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateUserNickNameAlias(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     map[string]string
+		want     int
+		wantNick string
+	}{
+		{"nick_name", map[string]string{"nick_name": "Alice L"}, http.StatusOK, "Alice L"},
+		{"旧版字段 name", map[string]string{"name": "Alice L"}, http.StatusOK, "Alice L"},
+		{"同时提供时以 nick_name 为准", map[string]string{"nick_name": "Alice L", "name": "Old"}, http.StatusOK, "Alice L"},
+		{"缺少昵称", map[string]string{}, http.StatusBadRequest, "Alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := alice()
+			repo := setupUsers(t, user)
+			r := newEngine()
+			r.PUT("/v1/users/:id", UpdateUser)
+
+			w := doJSON(r, http.MethodPut, "/v1/users/1", tt.body, tokenFor(t, user))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if got := repo.Users()[0].NickName; got != tt.wantNick {
+				t.Errorf("NickName = %q, want %q", got, tt.wantNick)
+			}
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	user := alice()
+	repo := setupUsers(t, user)
+	r := newEngine()
+	r.DELETE("/v1/users/:id", DeleteUser)
+
+	w := doJSON(r, http.MethodDelete, "/v1/users/1", nil, tokenFor(t, user))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204, body: %s", w.Code, w.Body.String())
+	}
+	if n := len(repo.Users()); n != 0 {
+		t.Errorf("repository has %d users after delete, want 0", n)
+	}
+}
+
+func TestDeleteUsers(t *testing.T) {
+	tooMany := make([]uint, 101)
+	for i := range tooMany {
+		tooMany[i] = uint(i + 1)
+	}
+	tests := []struct {
+		name         string
+		body         any
+		want         int
+		wantDeleted  int
+		wantNotFound []uint
+	}{
+		{"全部删除", map[string][]uint{"ids": {1, 2}}, http.StatusOK, 2, []uint{}},
+		{"部分不存在", map[string][]uint{"ids": {2, 404}}, http.StatusOK, 1, []uint{404}},
+		{"空列表", map[string][]uint{"ids": {}}, http.StatusBadRequest, 0, nil},
+		{"缺少 ids", map[string]any{}, http.StatusBadRequest, 0, nil},
+		{"超过数量上限", map[string][]uint{"ids": tooMany}, http.StatusBadRequest, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := alice()
+			admin.Role = models.RoleAdmin
+			setupUsers(t, admin, &models.User{ID: 2, Username: "bob", NickName: "Bob", Email: "bob@example.com", Password: "hash"})
+			r := newEngine()
+			r.DELETE("/v1/users", DeleteUsers)
+
+			w := doJSON(r, http.MethodDelete, "/v1/users", tt.body, tokenFor(t, admin))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var resp service.DeleteUsersResp
+			decodeData(t, w, &resp)
+			if resp.Deleted != tt.wantDeleted || !slices.Equal(resp.NotFound, tt.wantNotFound) {
+				t.Errorf("resp = %+v, want deleted %d, not_found %v", resp, tt.wantDeleted, tt.wantNotFound)
+			}
+		})
+	}
+}
+
+func TestGetAllUsers(t *testing.T) {
+	token := tokenFor(t, alice())
+	tests := []struct {
+		name      string
+		users     []*models.User
+		wantTotal int64
+	}{
+		{"没有用户", nil, 0},
+		{"有用户", []*models.User{
+			alice(),
+			{ID: 2, Username: "bob", NickName: "Bob", Email: "bob@example.com", Password: "hash"},
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupUsers(t, tt.users...)
+			r := newEngine()
+			r.GET("/v1/users", GetAllUsers)
+
+			w := doJSON(r, http.MethodGet, "/v1/users", nil, token)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200, body: %s", w.Code, w.Body.String())
+			}
+			var page response.PagedResponse[models.User]
+			decodeData(t, w, &page)
+			if page.Total != tt.wantTotal || int64(len(page.Data)) != tt.wantTotal {
+				t.Errorf("total = %d, len(data) = %d, want %d", page.Total, len(page.Data), tt.wantTotal)
+			}
+			if page.Page != 1 || page.Size != 20 {
+				t.Errorf("page = %d, size = %d, want 1, 20", page.Page, page.Size)
+			}
+		})
+	}
+}
+
+func TestGetAllUsersPagination(t *testing.T) {
+	users := make([]*models.User, 25)
+	for i := range users {
+		id := uint(i + 1)
+		users[i] = &models.User{ID: id, Username: fmt.Sprintf("user%d", id), NickName: "User", Email: fmt.Sprintf("user%d@example.com", id), Password: "hash"}
+	}
+	setupUsers(t, users...)
+	token := tokenFor(t, users[0])
+	r := newEngine()
+	r.GET("/v1/users", GetAllUsers)
+
+	tests := []struct {
+		name               string
+		query              string
+		want               int
+		wantPage, wantSize int
+		wantLen            int
+	}{
+		{"默认分页", "", http.StatusOK, 1, 20, 20},
+		{"最后一页", "?page=2&size=20", http.StatusOK, 2, 20, 5},
+		{"超出范围", "?page=5&size=10", http.StatusOK, 5, 10, 0},
+		{"size 超过上限", "?size=1000", http.StatusOK, 1, 200, 25},
+		{"page 为 0", "?page=0", http.StatusBadRequest, 0, 0, 0},
+		{"size 为负数", "?size=-1", http.StatusBadRequest, 0, 0, 0},
+		{"page 不是数字", "?page=abc", http.StatusBadRequest, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doJSON(r, http.MethodGet, "/v1/users"+tt.query, nil, token)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var page response.PagedResponse[models.User]
+			decodeData(t, w, &page)
+			if page.Total != 25 || page.Page != tt.wantPage || page.Size != tt.wantSize || len(page.Data) != tt.wantLen {
+				t.Errorf("total = %d, page = %d, size = %d, len(data) = %d, want 25, %d, %d, %d",
+					page.Total, page.Page, page.Size, len(page.Data), tt.wantPage, tt.wantSize, tt.wantLen)
+			}
+		})
+	}
+}
+
+// hasKey 递归检查 JSON 值中是否存在名为 key 的字段
+func hasKey(v any, key string) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if k == key || hasKey(child, key) {
+				return true
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if hasKey(child, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestUserResponsesOmitPassword(t *testing.T) {
+	tests := []struct {
+		name, method, path string
+		body               any
+		want               int
+	}{
+		{"搜索", http.MethodGet, "/v1/users/search?q=ali", nil, http.StatusOK},
+		{"详情", http.MethodGet, "/v1/users/1", nil, http.StatusOK},
+		{"分页列表", http.MethodGet, "/v1/users", nil, http.StatusOK},
+		{"创建", http.MethodPost, "/v1/users", map[string]string{"username": "bob", "nick_name": "Bob", "password": "secret123", "email": "bob@example.com"}, http.StatusCreated},
+		{"注册", http.MethodPost, "/v1/register", map[string]string{"username": "bob", "nick_name": "Bob", "password": "secret123", "email": "bob@example.com"}, http.StatusOK},
+		{"更新", http.MethodPut, "/v1/users/1", map[string]string{"nick_name": "Alice2"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := alice()
+			setupUsers(t, user)
+			r := newEngine()
+			r.GET("/v1/users/search", SearchUsers)
+			r.GET("/v1/users/:id", GetUserByID)
+			r.GET("/v1/users", GetAllUsers)
+			r.POST("/v1/users", CreateUser)
+			r.POST("/v1/register", Register)
+			r.PUT("/v1/users/:id", UpdateUser)
+
+			w := doJSON(r, tt.method, tt.path, tt.body, tokenFor(t, user))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["data"] == nil {
+				t.Fatalf("response has no data: %s", w.Body.String())
+			}
+			// 密码哈希不能出现在任何用户响应中
+			if hasKey(body, "password") {
+				t.Errorf("response contains password: %s", w.Body.String())
+			}
+		})
+	}
+}
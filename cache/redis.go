@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gojet/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout 单次 Redis 操作超时时间，Redis 不可用时尽快回退到数据库
+const redisTimeout = 200 * time.Millisecond
+
+// RedisUserCache 基于 Redis 的用户缓存，多个实例共享，用户以 JSON 格式保存
+// Redis 出错时按未命中处理并记录日志，不影响正常请求
+type RedisUserCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisUserCache 连接 Redis 并创建用户缓存，连接失败时返回错误
+func NewRedisUserCache(addr, password string, db int, ttl time.Duration) (*RedisUserCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("连接 Redis 失败: %w", err)
+	}
+	return &RedisUserCache{client: client, ttl: ttl}, nil
+}
+
+// key 用户缓存键
+func (c *RedisUserCache) key(id uint) string {
+	return fmt.Sprintf("gojet:user:%d", id)
+}
+
+// Get 获取缓存的用户
+func (c *RedisUserCache) Get(id uint) (*models.User, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.key(id)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Warn("读取用户缓存失败", "id", id, "error", err)
+		}
+		return nil, false
+	}
+
+	var user models.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		slog.Warn("解析用户缓存失败", "id", id, "error", err)
+		return nil, false
+	}
+	return &user, true
+}
+
+// Set 写入用户缓存
+func (c *RedisUserCache) Set(user *models.User) {
+	data, err := json.Marshal(user)
+	if err != nil {
+		slog.Warn("序列化用户缓存失败", "id", user.ID, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := c.client.Set(ctx, c.key(user.ID), data, c.ttl).Err(); err != nil {
+		slog.Warn("写入用户缓存失败", "id", user.ID, "error", err)
+	}
+}
+
+// Delete 删除用户缓存，失败时缓存最长在 TTL 后过期
+func (c *RedisUserCache) Delete(id uint) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := c.client.Del(ctx, c.key(id)).Err(); err != nil {
+		slog.Error("删除用户缓存失败", "id", id, "error", err)
+	}
+}
+
+// Close 关闭 Redis 连接
+func (c *RedisUserCache) Close() error {
+	return c.client.Close()
+}
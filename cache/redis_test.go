@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"gojet/models"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestRedisCache 使用内存 Redis 创建用户缓存
+func newTestRedisCache(t *testing.T, ttl time.Duration) (*RedisUserCache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	c, err := NewRedisUserCache(mr.Addr(), "", 0, ttl)
+	if err != nil {
+		t.Fatalf("NewRedisUserCache: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, mr
+}
+
+func TestRedisUserCache(t *testing.T) {
+	c, mr := newTestRedisCache(t, time.Minute)
+
+	if _, ok := c.Get(1); ok {
+		t.Fatal("Get on empty cache hit")
+	}
+
+	c.Set(&models.User{ID: 1, Username: "alice", NickName: "Alice"})
+	user, ok := c.Get(1)
+	if !ok || user.Username != "alice" || user.NickName != "Alice" {
+		t.Fatalf("Get(1) = %+v, %v, want alice", user, ok)
+	}
+	if ttl := mr.TTL(c.key(1)); ttl != time.Minute {
+		t.Errorf("TTL = %v, want 1m", ttl)
+	}
+
+	c.Delete(1)
+	if _, ok := c.Get(1); ok {
+		t.Error("Get after Delete hit")
+	}
+}
+
+func TestRedisUserCacheExpires(t *testing.T) {
+	c, mr := newTestRedisCache(t, time.Minute)
+
+	c.Set(&models.User{ID: 1, Username: "alice"})
+	mr.FastForward(time.Minute)
+	if _, ok := c.Get(1); ok {
+		t.Error("Get after TTL hit")
+	}
+}
+
+func TestRedisUserCacheMissOnError(t *testing.T) {
+	c, mr := newTestRedisCache(t, time.Minute)
+	c.Set(&models.User{ID: 1, Username: "alice"})
+
+	// 缓存中的数据无法解析时按未命中处理
+	mr.Set(c.key(2), "not json")
+	if _, ok := c.Get(2); ok {
+		t.Error("Get with invalid JSON hit")
+	}
+
+	// Redis 不可用时按未命中处理，回退到数据库
+	mr.Close()
+	if _, ok := c.Get(1); ok {
+		t.Error("Get with Redis down hit")
+	}
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"gojet/models"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// UserCache 用户缓存接口 - 按用户 ID 缓存用户信息
+type UserCache interface {
+	Get(id uint) (*models.User, bool)
+	Set(user *models.User)
+	Delete(id uint)
+	Close() error
+}
+
+// NoopUserCache 空实现 - 未启用缓存时使用
+type NoopUserCache struct{}
+
+// Get 始终未命中
+func (NoopUserCache) Get(uint) (*models.User, bool) { return nil, false }
+
+// Set 不做任何处理
+func (NoopUserCache) Set(*models.User) {}
+
+// Delete 不做任何处理
+func (NoopUserCache) Delete(uint) {}
+
+// Close 无需释放资源
+func (NoopUserCache) Close() error { return nil }
+
+// entry 缓存条目，记录写入时的过期时间
+type entry struct {
+	user      models.User
+	expiresAt time.Time
+}
+
+// InMemoryUserCache 基于 LRU 的进程内用户缓存，适用于没有 Redis 的部署
+type InMemoryUserCache struct {
+	lru *lru.Cache[uint, entry]
+	ttl time.Duration
+}
+
+// NewInMemoryUserCache 创建进程内用户缓存
+func NewInMemoryUserCache(size int, ttl time.Duration) (*InMemoryUserCache, error) {
+	c, err := lru.New[uint, entry](size)
+	if err != nil {
+		return nil, fmt.Errorf("创建 LRU 缓存失败: %w", err)
+	}
+	return &InMemoryUserCache{lru: c, ttl: ttl}, nil
+}
+
+// Get 获取缓存的用户，条目过期时视为未命中并将其移除
+func (c *InMemoryUserCache) Get(id uint) (*models.User, bool) {
+	e, ok := c.lru.Get(id)
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		c.lru.Remove(id)
+		return nil, false
+	}
+	// 返回副本，避免调用方修改缓存内容
+	user := e.user
+	return &user, true
+}
+
+// Set 写入用户缓存
+func (c *InMemoryUserCache) Set(user *models.User) {
+	c.lru.Add(user.ID, entry{user: *user, expiresAt: time.Now().Add(c.ttl)})
+}
+
+// Delete 删除用户缓存
+func (c *InMemoryUserCache) Delete(id uint) {
+	c.lru.Remove(id)
+}
+
+// Close 清空缓存
+func (c *InMemoryUserCache) Close() error {
+	c.lru.Purge()
+	return nil
+}
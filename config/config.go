@@ -1,10 +1,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/goccy/go-yaml"
 )
 
@@ -14,24 +21,55 @@ type Config struct {
 	Database DatabaseConfig `yaml:"database"` // 数据库配置
 	Logging  LoggingConfig  `yaml:"logging"`  // 日志配置
 	JWT      JWTConfig      `yaml:"jwt"`      // JWT 配置
+	NATS     NATSConfig     `yaml:"nats"`     // NATS 消息配置
+	Cache    CacheConfig    `yaml:"cache"`    // 缓存配置
+
+	RateLimiting RateLimitingConfig `yaml:"rate_limiting"` // 限流配置
+	CORS         CORSConfig         `yaml:"cors"`          // 跨域配置
+	Tracing      TracingConfig      `yaml:"tracing"`       // 链路追踪配置
+
+	Features map[string]bool `yaml:"features"` // 可选功能开关，例如 swagger
 }
 
 // AppConfig 应用配置 - 定义应用的基本信息
 type AppConfig struct {
-	Name    string `yaml:"name"`    // 应用名称
-	Version string `yaml:"version"` // 应用版本
-	Port    int    `yaml:"port"`    // 服务端口
-	Mode    string `yaml:"mode"`    // 运行模式 (debug/release/test)
+	Name     string `yaml:"name"`      // 应用名称
+	Version  string `yaml:"version"`   // 应用版本
+	Port     int    `yaml:"port"`      // 服务端口
+	GRPCPort int    `yaml:"grpc_port"` // gRPC 服务端口（0 表示不启动）
+	Mode     string `yaml:"mode"`      // 运行模式 (debug/release/test)
+
+	SeedEnabled    bool     `yaml:"seed_enabled"`    // 是否允许写入初始数据
+	SeedOnStartup  bool     `yaml:"seed_on_startup"` // 启动时是否自动写入初始数据
+	TrustedProxies []string `yaml:"trusted_proxies"` // 可信代理 IP/网段，为空时不信任任何转发头
+
+	MaxRequestBodySize int64 `yaml:"max_request_body_size"` // 请求体最大字节数（默认 1MB）
+	GzipMinBytes       int   `yaml:"gzip_min_bytes"`        // 响应体达到该字节数时 gzip 压缩，0 表示不压缩
+	MaxBatchDelete     int   `yaml:"max_batch_delete"`      // 批量删除用户单次最多 ID 数（默认 100）
+
+	ReadTimeout  string `yaml:"read_timeout"`  // 读取请求（含请求体）的超时时间，例如 15s
+	WriteTimeout string `yaml:"write_timeout"` // 写入响应的超时时间，例如 30s
+	IdleTimeout  string `yaml:"idle_timeout"`  // keep-alive 空闲连接超时时间，例如 120s
+
+	RequestTimeoutSeconds int `yaml:"request_timeout_seconds"` // 单个请求的处理时限（秒），超时返回 503，0 表示不限制
 }
 
-// DatabaseConfig 数据库配置 - PostgreSQL 连接参数
+// DatabaseConfig 数据库配置 - PostgreSQL/MySQL 连接参数
 type DatabaseConfig struct {
+	Driver   string `yaml:"driver"`   // 数据库类型: postgres/mysql（MySQL 同时适用于 MariaDB）
 	Host     string `yaml:"host"`     // 数据库主机地址
 	Port     int    `yaml:"port"`     // 数据库端口
 	User     string `yaml:"user"`     // 数据库用户名
 	Password string `yaml:"password"` // 数据库密码
 	DBName   string `yaml:"dbname"`   // 数据库名称
-	SSLMode  string `yaml:"sslmode"`  // SSL 连接模式
+	SSLMode  string `yaml:"sslmode"`  // SSL 连接模式，使用 PostgreSQL 的取值，MySQL 按相近语义转换
+
+	ReplicaDSN        string `yaml:"replica_dsn"`         // 只读副本 DSN，为空时读写均使用主库
+	PoolWaitThreshold int64  `yaml:"pool_wait_threshold"` // 连接池等待次数告警阈值（默认 10）
+
+	MaxOpenConns           int `yaml:"max_open_conns"`            // 最大打开连接数（默认 25）
+	MaxIdleConns           int `yaml:"max_idle_conns"`            // 最大空闲连接数（默认 10）
+	ConnMaxLifetimeSeconds int `yaml:"conn_max_lifetime_seconds"` // 连接最长复用时间，单位秒（默认 300）
 }
 
 // LoggingConfig 日志配置 - 定义日志行为
@@ -39,17 +77,151 @@ type LoggingConfig struct {
 	Level    string `yaml:"level"`     // 日志级别 (debug/info/warn/error)
 	Output   string `yaml:"output"`    // 日志输出位置 (stdout/file/both)
 	FilePath string `yaml:"file_path"` // 日志文件路径
+	Format   string `yaml:"format"`    // 日志格式 (json/text)
+
+	MaxSizeMB  int  `yaml:"max_size_mb"`  // 单个日志文件最大大小（MB），超过后切割
+	MaxBackups int  `yaml:"max_backups"`  // 保留的切割文件数量，0 表示不按数量清理
+	MaxAgeDays int  `yaml:"max_age_days"` // 切割文件保留天数，0 表示不按时间清理
+	Compress   bool `yaml:"compress"`     // 是否 gzip 压缩切割后的文件
+
+	SkipPaths []string `yaml:"skip_paths"` // 不记录请求日志的路径（非 200 响应仍会记录）
 }
 
 // JWTConfig JWT 配置 - 定义 JWT token 相关参数
 type JWTConfig struct {
 	Secret      string `yaml:"secret"`       // JWT 签名密钥
 	ExpireHours int    `yaml:"expire_hours"` // Token 过期时间（小时）
+	CookieName  string `yaml:"cookie_name"`  // 存放 Token 的 cookie 名称，为空时不读取 cookie
+
+	RefreshExpireHours int `yaml:"refresh_expire_hours"` // Refresh token 过期时间（小时）
+
+	BlacklistType string `yaml:"blacklist_type"` // 注销 token 黑名单类型: memory/redis，redis 使用 cache 的连接配置
+}
+
+// NATSConfig NATS 配置 - 用户事件发布
+type NATSConfig struct {
+	URL string `yaml:"url"` // NATS 服务地址，为空时不发布事件
+}
+
+// CacheConfig 缓存配置 - 用户信息缓存
+type CacheConfig struct {
+	Type         string `yaml:"type"`           // 缓存类型 (none/memory/redis)
+	InMemorySize int    `yaml:"in_memory_size"` // 进程内缓存最大条目数
+	TTL          string `yaml:"ttl"`            // 缓存过期时间，例如 5m
+
+	Address  string `yaml:"address"`  // Redis 地址，例如 localhost:6379（type 为 redis 时必填）
+	Password string `yaml:"password"` // Redis 密码
+	DB       int    `yaml:"db"`       // Redis 数据库编号
+}
+
+// RateLimitingConfig 限流配置 - 基于令牌桶，超出限制时返回 429
+type RateLimitingConfig struct {
+	Enabled   bool `yaml:"enabled"`    // 是否启用限流
+	GlobalRPS int  `yaml:"global_rps"` // 所有客户端合计每秒请求数
+	BurstSize int  `yaml:"burst_size"` // 全局令牌桶容量，允许的瞬时突发请求数
+
+	PerIPRPS   int `yaml:"per_ip_rps"`   // /v1/users 接口单个 IP 每秒请求数，0 表示不按 IP 限流
+	PerIPBurst int `yaml:"per_ip_burst"` // /v1/users 接口单个 IP 的令牌桶容量
+}
+
+// CORSConfig 跨域配置 - AllowedOrigins 为空时不启用 CORS
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins"`   // 允许的来源，例如 https://app.example.com；"*" 表示任意来源
+	AllowedMethods   []string `yaml:"allowed_methods"`   // 预检请求允许的方法
+	AllowedHeaders   []string `yaml:"allowed_headers"`   // 预检请求允许的请求头
+	AllowCredentials bool     `yaml:"allow_credentials"` // 是否允许携带 cookie 等凭证，不能与 "*" 同时生效
+	MaxAge           int      `yaml:"max_age"`           // 预检结果缓存时间（秒），0 表示不设置
+}
+
+// Enabled 是否配置了允许的来源
+func (c *CORSConfig) Enabled() bool {
+	return len(c.AllowedOrigins) > 0
+}
+
+// TracingConfig 链路追踪配置 - 通过 OTLP gRPC 导出到 OpenTelemetry Collector
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`      // 是否启用链路追踪，关闭时不产生任何 span
+	Endpoint    string  `yaml:"endpoint"`     // OTLP gRPC 地址，例如 localhost:4317
+	Insecure    bool    `yaml:"insecure"`     // 是否使用明文连接（collector 未启用 TLS 时）
+	SampleRatio float64 `yaml:"sample_ratio"` // 采样比例 0-1，上游已决定采样时沿用上游的决定
 }
 
-// LoadConfig 加载配置 - 从 YAML 文件和环境变量读取配置
+// appModes 支持的运行模式，与 gin 的模式一致（gin.SetMode 遇到其他取值会 panic），为空时按 debug 处理
+var appModes = []string{"", "debug", "release", "test"}
+
+// 支持的数据库类型
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+)
+
+// sslModes PostgreSQL 支持的 sslmode 取值
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// DefaultConfig 默认配置 - 配置文件和环境变量均未设置的字段使用这些值
+// 数据库默认连接本机；JWT 密钥没有安全的默认值，必须通过配置文件或 JWT_SECRET 提供
+func DefaultConfig() *Config {
+	return &Config{
+		App: AppConfig{
+			Port:           8080,
+			Mode:           "debug",
+			SeedEnabled:    true,
+			SeedOnStartup:  true,
+			ReadTimeout:    "15s",
+			WriteTimeout:   "30s",
+			IdleTimeout:    "120s",
+			GzipMinBytes:   1024,
+			MaxBatchDelete: 100,
+
+			RequestTimeoutSeconds: 20,
+		},
+		Database: DatabaseConfig{
+			Driver:  DriverPostgres,
+			Host:    "localhost",
+			Port:    5432,
+			SSLMode: "disable",
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Output: "stdout",
+			Format: "json",
+
+			MaxSizeMB:  100,
+			MaxBackups: 10,
+			MaxAgeDays: 30,
+			Compress:   true,
+		},
+		JWT: JWTConfig{
+			ExpireHours:        24,
+			RefreshExpireHours: 720,
+			BlacklistType:      "memory",
+		},
+		Cache: CacheConfig{
+			Type:         "none",
+			InMemorySize: 1000,
+			TTL:          "5m",
+		},
+		RateLimiting: RateLimitingConfig{
+			GlobalRPS:  100,
+			BurstSize:  200,
+			PerIPRPS:   10,
+			PerIPBurst: 20,
+		},
+		Tracing: TracingConfig{
+			SampleRatio: 1,
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "If-None-Match", "X-Request-ID"},
+			MaxAge:         600,
+		},
+	}
+}
+
+// LoadConfig 加载配置 - 以默认配置为基础，依次合并 YAML 文件和环境变量
+// configPath 为空时只使用默认配置与环境变量，此时至少需要设置 JWT_SECRET
 func LoadConfig(configPath string) (*Config, error) {
-	config := &Config{}
+	config := DefaultConfig()
 
 	// 从 YAML 文件加载配置
 	if configPath != "" {
@@ -58,17 +230,159 @@ func LoadConfig(configPath string) (*Config, error) {
 			return nil, fmt.Errorf("读取配置文件失败: %w", err)
 		}
 
-		if err := yaml.Unmarshal(data, config); err != nil {
+		// 空文档（或只有注释）会把整个结构体置零，需要先判断再合并
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
 			return nil, fmt.Errorf("解析配置文件失败: %w", err)
 		}
+		if doc != nil {
+			if err := yaml.Unmarshal(data, config); err != nil {
+				return nil, fmt.Errorf("解析配置文件失败: %w", err)
+			}
+		}
 	}
 
 	// 使用环境变量覆盖配置文件中的设置
 	config.overrideWithEnv()
 
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("配置校验失败: %w", err)
+	}
+
 	return config, nil
 }
 
+// Validate 校验配置项之间的约束，返回所有不合法的配置项
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !validPort(c.App.Port) {
+		errs = append(errs, fmt.Errorf("app.port 必须在 1 到 65535 之间，当前为 %d", c.App.Port))
+	}
+	if c.App.GRPCPort != 0 && !validPort(c.App.GRPCPort) {
+		errs = append(errs, fmt.Errorf("app.grpc_port 必须在 1 到 65535 之间（0 表示不启动），当前为 %d", c.App.GRPCPort))
+	}
+	if !slices.Contains(appModes, c.App.Mode) {
+		errs = append(errs, fmt.Errorf("app.mode 不支持 %s，可选 debug/release/test", c.App.Mode))
+	}
+
+	switch c.Database.Driver {
+	case DriverPostgres, DriverMySQL:
+	default:
+		errs = append(errs, fmt.Errorf("database.driver 不支持 %s，可选 %s/%s", c.Database.Driver, DriverPostgres, DriverMySQL))
+	}
+	if c.Database.Host == "" {
+		errs = append(errs, errors.New("database.host 不能为空"))
+	}
+	if !validPort(c.Database.Port) {
+		errs = append(errs, fmt.Errorf("database.port 必须在 1 到 65535 之间，当前为 %d", c.Database.Port))
+	}
+
+	if strings.TrimSpace(c.JWT.Secret) == "" {
+		errs = append(errs, errors.New("jwt.secret 不能为空，可通过 JWT_SECRET 环境变量设置"))
+	}
+	if c.JWT.ExpireHours <= 0 || c.JWT.RefreshExpireHours <= 0 {
+		errs = append(errs, errors.New("jwt.expire_hours 与 jwt.refresh_expire_hours 必须大于 0"))
+	}
+
+	timeouts := []struct{ key, value string }{
+		{"app.read_timeout", c.App.ReadTimeout},
+		{"app.write_timeout", c.App.WriteTimeout},
+		{"app.idle_timeout", c.App.IdleTimeout},
+	}
+	for _, t := range timeouts {
+		if _, err := time.ParseDuration(t.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s 不是合法的时间间隔: %w", t.key, err))
+		}
+	}
+
+	if c.App.RequestTimeoutSeconds < 0 {
+		errs = append(errs, errors.New("app.request_timeout_seconds 不能为负数"))
+	} else if writeTimeout, err := time.ParseDuration(c.App.WriteTimeout); err == nil &&
+		c.App.GetRequestTimeout() >= writeTimeout && c.App.RequestTimeoutSeconds > 0 {
+		// 超过 write_timeout 时连接会先被关闭，客户端收不到 503
+		errs = append(errs, fmt.Errorf("app.request_timeout_seconds 必须小于 app.write_timeout (%s)", c.App.WriteTimeout))
+	}
+
+	if c.App.GzipMinBytes < 0 {
+		errs = append(errs, errors.New("app.gzip_min_bytes 不能为负数"))
+	}
+
+	if !slices.Contains(sslModes, c.Database.SSLMode) {
+		errs = append(errs, fmt.Errorf("database.sslmode 不支持 %s，可选 %s", c.Database.SSLMode, strings.Join(sslModes, "/")))
+	}
+
+	switch strings.ToLower(c.Logging.Output) {
+	case "file", "both":
+		if c.Logging.FilePath == "" {
+			errs = append(errs, fmt.Errorf("logging.output 为 %s 时必须配置 logging.file_path", c.Logging.Output))
+		}
+	}
+
+	if c.Logging.MaxSizeMB <= 0 {
+		errs = append(errs, errors.New("logging.max_size_mb 必须大于 0"))
+	}
+	if c.Logging.MaxBackups < 0 || c.Logging.MaxAgeDays < 0 {
+		errs = append(errs, errors.New("logging.max_backups 与 logging.max_age_days 不能为负数"))
+	}
+
+	switch strings.ToLower(c.Logging.Format) {
+	case "", "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("logging.format 不支持 %s，可选 json/text", c.Logging.Format))
+	}
+
+	if c.RateLimiting.Enabled {
+		if c.RateLimiting.GlobalRPS <= 0 || c.RateLimiting.BurstSize <= 0 {
+			errs = append(errs, errors.New("rate_limiting.global_rps 与 rate_limiting.burst_size 必须大于 0"))
+		}
+		if c.RateLimiting.PerIPRPS < 0 || (c.RateLimiting.PerIPRPS > 0 && c.RateLimiting.PerIPBurst <= 0) {
+			errs = append(errs, errors.New("rate_limiting.per_ip_rps 不能为负数，启用时 rate_limiting.per_ip_burst 必须大于 0"))
+		}
+	}
+
+	switch strings.ToLower(c.Cache.Type) {
+	case "", "none":
+	case "memory", "redis":
+		if _, err := time.ParseDuration(c.Cache.TTL); err != nil {
+			errs = append(errs, fmt.Errorf("cache.ttl 不是合法的时间间隔: %w", err))
+		}
+		if strings.EqualFold(c.Cache.Type, "redis") && c.Cache.Address == "" {
+			errs = append(errs, errors.New("cache.type 为 redis 时必须配置 cache.address"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("cache.type 不支持 %s，可选 none/memory/redis", c.Cache.Type))
+	}
+
+	switch strings.ToLower(c.JWT.BlacklistType) {
+	case "", "memory":
+	case "redis":
+		if c.Cache.Address == "" {
+			errs = append(errs, errors.New("jwt.blacklist_type 为 redis 时必须配置 cache.address"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("jwt.blacklist_type 不支持 %s，可选 memory/redis", c.JWT.BlacklistType))
+	}
+
+	if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+		errs = append(errs, errors.New("tracing.enabled 为 true 时必须配置 tracing.endpoint"))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio 必须在 0 到 1 之间，当前为 %v", c.Tracing.SampleRatio))
+	}
+
+	if c.CORS.MaxAge < 0 {
+		errs = append(errs, errors.New("cors.max_age 不能为负数"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validPort 判断端口号是否在 1-65535 之间
+func validPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
 // overrideWithEnv 使用环境变量覆盖配置 - 优先级：环境变量 > 配置文件
 func (c *Config) overrideWithEnv() {
 	if val := os.Getenv("APP_NAME"); val != "" {
@@ -79,11 +393,61 @@ func (c *Config) overrideWithEnv() {
 			c.App.Port = port
 		}
 	}
+	if val := os.Getenv("APP_GRPC_PORT"); val != "" {
+		if port, err := strconv.Atoi(val); err == nil {
+			c.App.GRPCPort = port
+		}
+	}
 	if val := os.Getenv("APP_MODE"); val != "" {
 		c.App.Mode = val
 	}
+	if val := os.Getenv("TRUSTED_PROXIES"); val != "" {
+		c.App.TrustedProxies = strings.Split(val, ",")
+	}
+	if val := os.Getenv("APP_MAX_REQUEST_BODY_SIZE"); val != "" {
+		if size, err := strconv.ParseInt(val, 10, 64); err == nil {
+			c.App.MaxRequestBodySize = size
+		}
+	}
+	if val := os.Getenv("APP_MAX_BATCH_DELETE"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			c.App.MaxBatchDelete = n
+		}
+	}
+	if val := os.Getenv("APP_GZIP_MIN_BYTES"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			c.App.GzipMinBytes = n
+		}
+	}
+	if val := os.Getenv("APP_REQUEST_TIMEOUT"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			c.App.RequestTimeoutSeconds = n
+		}
+	}
+	if val := os.Getenv("APP_READ_TIMEOUT"); val != "" {
+		c.App.ReadTimeout = val
+	}
+	if val := os.Getenv("APP_WRITE_TIMEOUT"); val != "" {
+		c.App.WriteTimeout = val
+	}
+	if val := os.Getenv("APP_IDLE_TIMEOUT"); val != "" {
+		c.App.IdleTimeout = val
+	}
+	if val := os.Getenv("APP_SEED_ENABLED"); val != "" {
+		if enabled, err := strconv.ParseBool(val); err == nil {
+			c.App.SeedEnabled = enabled
+		}
+	}
+	if val := os.Getenv("APP_SEED_ON_STARTUP"); val != "" {
+		if enabled, err := strconv.ParseBool(val); err == nil {
+			c.App.SeedOnStartup = enabled
+		}
+	}
 
 	// 数据库配置
+	if val := os.Getenv("DB_DRIVER"); val != "" {
+		c.Database.Driver = strings.ToLower(val)
+	}
 	if val := os.Getenv("DB_HOST"); val != "" {
 		c.Database.Host = val
 	}
@@ -102,7 +466,35 @@ func (c *Config) overrideWithEnv() {
 		c.Database.DBName = val
 	}
 	if val := os.Getenv("DB_SSLMODE"); val != "" {
-		c.Database.SSLMode = val
+		if slices.Contains(sslModes, val) {
+			c.Database.SSLMode = val
+		} else {
+			slog.Warn("忽略不合法的 DB_SSLMODE 环境变量", "value", val, "allowed", sslModes)
+		}
+	}
+	if val := os.Getenv("DB_REPLICA_DSN"); val != "" {
+		c.Database.ReplicaDSN = val
+	}
+	if val := os.Getenv("DB_POOL_WAIT_THRESHOLD"); val != "" {
+		if threshold, err := strconv.ParseInt(val, 10, 64); err == nil {
+			c.Database.PoolWaitThreshold = threshold
+		}
+	}
+
+	if val := os.Getenv("DB_MAX_OPEN"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			c.Database.MaxOpenConns = n
+		}
+	}
+	if val := os.Getenv("DB_MAX_IDLE"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			c.Database.MaxIdleConns = n
+		}
+	}
+	if val := os.Getenv("DB_CONN_LIFETIME"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil {
+			c.Database.ConnMaxLifetimeSeconds = seconds
+		}
 	}
 
 	// 日志配置
@@ -115,6 +507,32 @@ func (c *Config) overrideWithEnv() {
 	if val := os.Getenv("LOG_FILE_PATH"); val != "" {
 		c.Logging.FilePath = val
 	}
+	if val := os.Getenv("LOG_FORMAT"); val != "" {
+		c.Logging.Format = val
+	}
+	if val := os.Getenv("LOG_MAX_SIZE_MB"); val != "" {
+		if size, err := strconv.Atoi(val); err == nil {
+			c.Logging.MaxSizeMB = size
+		}
+	}
+	if val := os.Getenv("LOG_MAX_BACKUPS"); val != "" {
+		if backups, err := strconv.Atoi(val); err == nil {
+			c.Logging.MaxBackups = backups
+		}
+	}
+	if val := os.Getenv("LOG_MAX_AGE_DAYS"); val != "" {
+		if days, err := strconv.Atoi(val); err == nil {
+			c.Logging.MaxAgeDays = days
+		}
+	}
+	if val := os.Getenv("LOG_COMPRESS"); val != "" {
+		if compress, err := strconv.ParseBool(val); err == nil {
+			c.Logging.Compress = compress
+		}
+	}
+	if val := os.Getenv("LOG_SKIP_PATHS"); val != "" {
+		c.Logging.SkipPaths = strings.Split(val, ",")
+	}
 
 	// JWT 配置
 	if val := os.Getenv("JWT_SECRET"); val != "" {
@@ -125,11 +543,226 @@ func (c *Config) overrideWithEnv() {
 			c.JWT.ExpireHours = hours
 		}
 	}
+	if val := os.Getenv("JWT_REFRESH_EXPIRE_HOURS"); val != "" {
+		if hours, err := strconv.Atoi(val); err == nil {
+			c.JWT.RefreshExpireHours = hours
+		}
+	}
+	if val := os.Getenv("JWT_COOKIE_NAME"); val != "" {
+		c.JWT.CookieName = val
+	}
+	if val := os.Getenv("JWT_BLACKLIST_TYPE"); val != "" {
+		c.JWT.BlacklistType = val
+	}
+
+	// NATS 配置
+	if val := os.Getenv("NATS_URL"); val != "" {
+		c.NATS.URL = val
+	}
+
+	// 缓存配置
+	if val := os.Getenv("CACHE_TYPE"); val != "" {
+		c.Cache.Type = val
+	}
+	if val := os.Getenv("CACHE_IN_MEMORY_SIZE"); val != "" {
+		if size, err := strconv.Atoi(val); err == nil {
+			c.Cache.InMemorySize = size
+		}
+	}
+	if val := os.Getenv("CACHE_TTL"); val != "" {
+		c.Cache.TTL = val
+	}
+	if val := os.Getenv("CACHE_ADDRESS"); val != "" {
+		c.Cache.Address = val
+	}
+	if val := os.Getenv("CACHE_PASSWORD"); val != "" {
+		c.Cache.Password = val
+	}
+	if val := os.Getenv("CACHE_DB"); val != "" {
+		if db, err := strconv.Atoi(val); err == nil {
+			c.Cache.DB = db
+		}
+	}
+
+	// 限流配置
+	if val := os.Getenv("RATE_LIMIT_ENABLED"); val != "" {
+		if enabled, err := strconv.ParseBool(val); err == nil {
+			c.RateLimiting.Enabled = enabled
+		}
+	}
+	if val := os.Getenv("RATE_LIMIT_GLOBAL_RPS"); val != "" {
+		if rps, err := strconv.Atoi(val); err == nil {
+			c.RateLimiting.GlobalRPS = rps
+		}
+	}
+	if val := os.Getenv("RATE_LIMIT_BURST_SIZE"); val != "" {
+		if burst, err := strconv.Atoi(val); err == nil {
+			c.RateLimiting.BurstSize = burst
+		}
+	}
+	if val := os.Getenv("RATE_LIMIT_PER_IP_RPS"); val != "" {
+		if rps, err := strconv.Atoi(val); err == nil {
+			c.RateLimiting.PerIPRPS = rps
+		}
+	}
+	if val := os.Getenv("RATE_LIMIT_PER_IP_BURST"); val != "" {
+		if burst, err := strconv.Atoi(val); err == nil {
+			c.RateLimiting.PerIPBurst = burst
+		}
+	}
+
+	// 跨域配置
+	if val := os.Getenv("CORS_ALLOWED_ORIGINS"); val != "" {
+		c.CORS.AllowedOrigins = strings.Split(val, ",")
+	}
+	if val := os.Getenv("CORS_ALLOW_CREDENTIALS"); val != "" {
+		if allow, err := strconv.ParseBool(val); err == nil {
+			c.CORS.AllowCredentials = allow
+		}
+	}
+
+	// 链路追踪配置
+	if val := os.Getenv("TRACING_ENABLED"); val != "" {
+		if enabled, err := strconv.ParseBool(val); err == nil {
+			c.Tracing.Enabled = enabled
+		}
+	}
+	if val := os.Getenv("TRACING_ENDPOINT"); val != "" {
+		c.Tracing.Endpoint = val
+	}
+	if val := os.Getenv("TRACING_INSECURE"); val != "" {
+		if insecure, err := strconv.ParseBool(val); err == nil {
+			c.Tracing.Insecure = insecure
+		}
+	}
+	if val := os.Getenv("TRACING_SAMPLE_RATIO"); val != "" {
+		if ratio, err := strconv.ParseFloat(val, 64); err == nil {
+			c.Tracing.SampleRatio = ratio
+		}
+	}
+
+	// 功能开关，逗号分隔的功能名称列表，列出的功能均视为开启
+	if val := os.Getenv("FEATURES"); val != "" {
+		if c.Features == nil {
+			c.Features = make(map[string]bool)
+		}
+		for _, name := range strings.Split(val, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				c.Features[name] = true
+			}
+		}
+	}
 }
 
-// GetDSN 获取数据库连接字符串 - 构建 PostgreSQL DSN 连接串
+// IsFeatureEnabled 判断功能开关是否开启，未配置的功能视为关闭
+func (c *Config) IsFeatureEnabled(name string) bool {
+	return c.Features[name]
+}
+
+// GetMaxRequestBodySize 获取请求体最大字节数，未配置时返回默认值 1MB
+func (a *AppConfig) GetMaxRequestBodySize() int64 {
+	if a.MaxRequestBodySize <= 0 {
+		return 1 << 20
+	}
+	return a.MaxRequestBodySize
+}
+
+// GetMaxBatchDelete 获取批量删除用户单次最多 ID 数，未配置时返回默认值 100
+func (a *AppConfig) GetMaxBatchDelete() int {
+	if a.MaxBatchDelete <= 0 {
+		return 100
+	}
+	return a.MaxBatchDelete
+}
+
+// GetRequestTimeout 获取单个请求的处理时限，0 表示不限制
+func (a *AppConfig) GetRequestTimeout() time.Duration {
+	return time.Duration(a.RequestTimeoutSeconds) * time.Second
+}
+
+// GetSkipPaths 获取不记录请求日志的路径，未配置时默认跳过健康检查和监控端点
+func (l *LoggingConfig) GetSkipPaths() []string {
+	if l.SkipPaths == nil {
+		return []string{"/v1/health", "/v1/health/live", "/v1/health/ready", "/metrics"}
+	}
+	return l.SkipPaths
+}
+
+// GetPoolWaitThreshold 获取连接池等待次数告警阈值，未配置时返回默认值 10
+func (db *DatabaseConfig) GetPoolWaitThreshold() int64 {
+	if db.PoolWaitThreshold <= 0 {
+		return 10
+	}
+	return db.PoolWaitThreshold
+}
+
+// GetMaxOpenConns 获取最大打开连接数，未配置时返回默认值 25
+func (db *DatabaseConfig) GetMaxOpenConns() int {
+	if db.MaxOpenConns <= 0 {
+		return 25
+	}
+	return db.MaxOpenConns
+}
+
+// GetMaxIdleConns 获取最大空闲连接数，未配置时返回默认值 10
+func (db *DatabaseConfig) GetMaxIdleConns() int {
+	if db.MaxIdleConns <= 0 {
+		return 10
+	}
+	return db.MaxIdleConns
+}
+
+// GetConnMaxLifetime 获取连接最长复用时间，未配置时返回默认值 5 分钟
+func (db *DatabaseConfig) GetConnMaxLifetime() time.Duration {
+	if db.ConnMaxLifetimeSeconds <= 0 {
+		return 300 * time.Second
+	}
+	return time.Duration(db.ConnMaxLifetimeSeconds) * time.Second
+}
+
+// GetDSN 获取数据库连接字符串 - 按数据库类型构建 PostgreSQL 或 MySQL DSN 连接串
 func (db *DatabaseConfig) GetDSN() string {
-	// 按照 PostgreSQL 的 DSN 格式拼接连接参数
-	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s ",
-		db.Host, db.User, db.Password, db.DBName, db.Port, db.SSLMode)
+	// 按照 database.driver 对应的 DSN 格式拼接连接参数
+	return db.dsn(db.Password)
+}
+
+// GetDSNRedacted 获取隐藏密码的连接字符串，用于日志和错误信息
+func (db *DatabaseConfig) GetDSNRedacted() string {
+	return db.dsn("[REDACTED]")
+}
+
+// dsn 使用指定的密码拼接连接字符串
+func (db *DatabaseConfig) dsn(password string) string {
+	if db.Driver == DriverMySQL {
+		return db.mysqlDSN(password)
+	}
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+		db.Host, db.User, password, db.DBName, db.Port, db.SSLMode)
+}
+
+// mysqlTLS sslmode 对应的 MySQL tls 参数，disable 时不设置
+var mysqlTLS = map[string]string{
+	"allow":       "preferred",
+	"prefer":      "preferred",
+	"require":     "skip-verify", // 与 PostgreSQL 一致：加密但不校验证书
+	"verify-ca":   "true",
+	"verify-full": "true",
+}
+
+// mysqlDSN 拼接 MySQL DSN，格式为 user:pass@tcp(host:port)/dbname?charset=utf8mb4&parseTime=true
+// 由驱动负责转义，密码中包含 @ / 等字符时同样可以正确解析
+func (db *DatabaseConfig) mysqlDSN(password string) string {
+	mc := mysql.NewConfig()
+	mc.User = db.User
+	mc.Passwd = password
+	mc.Net = "tcp"
+	mc.Addr = net.JoinHostPort(db.Host, strconv.Itoa(db.Port))
+	mc.DBName = db.DBName
+	// 不设置 parseTime 时 DATETIME 无法扫描到 time.Time
+	mc.ParseTime = true
+	// 默认 RowsAffected 只统计值发生变化的行，写入相同值时会被误判为记录不存在
+	mc.ClientFoundRows = true
+	mc.Params = map[string]string{"charset": "utf8mb4"}
+	mc.TLSConfig = mysqlTLS[db.SSLMode]
+	return mc.FormatDSN()
 }
@@ -0,0 +1,313 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// writeConfig 在临时目录写入配置文件并返回路径
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// setRequiredEnv 设置没有默认值的必填配置，并清除可能影响默认值的数据库地址
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("DB_HOST", "")
+	t.Setenv("JWT_SECRET", "test-secret")
+}
+
+func TestLoadConfigEmptyYAMLUsesDefaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	for name, content := range map[string]string{
+		"空文件":   "",
+		"只有注释":  "# 使用默认配置\n",
+		"部分配置项": "app:\n  name: gojet\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := LoadConfig(writeConfig(t, content))
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			checks := []struct {
+				key       string
+				got, want any
+			}{
+				{"app.port", cfg.App.Port, 8080},
+				{"app.mode", cfg.App.Mode, "debug"},
+				{"logging.level", cfg.Logging.Level, "info"},
+				{"logging.output", cfg.Logging.Output, "stdout"},
+				{"database.sslmode", cfg.Database.SSLMode, "disable"},
+				{"database.host", cfg.Database.Host, "localhost"},
+				{"database.port", cfg.Database.Port, 5432},
+				{"jwt.expire_hours", cfg.JWT.ExpireHours, 24},
+			}
+			for _, c := range checks {
+				if c.got != c.want {
+					t.Errorf("%s = %v, want %v", c.key, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateLogFilePath(t *testing.T) {
+	tests := []struct {
+		output, path string
+		wantErr      bool
+	}{
+		{"stdout", "", false},
+		{"file", "", true},
+		{"both", "", true},
+		{"file", "logs/app.log", false},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Logging.Output = tt.output
+		cfg.Logging.FilePath = tt.path
+
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(output=%s, file_path=%q) error = %v, wantErr %v", tt.output, tt.path, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLogFilePathEnvOverride(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("LOG_OUTPUT", "file")
+	t.Setenv("LOG_FILE_PATH", "/var/log/gojet/app.log")
+
+	cfg, err := LoadConfig(writeConfig(t, ""))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Logging.FilePath != "/var/log/gojet/app.log" {
+		t.Errorf("logging.file_path = %q", cfg.Logging.FilePath)
+	}
+}
+
+func TestLoadConfigEmptyPath(t *testing.T) {
+	t.Run("仅使用环境变量", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig("")
+		if err != nil {
+			t.Fatalf("LoadConfig: %v", err)
+		}
+		if cfg.App.Port != 8080 {
+			t.Errorf("app.port = %d, want 8080", cfg.App.Port)
+		}
+	})
+
+	t.Run("只设置 JWT 密钥", func(t *testing.T) {
+		t.Setenv("DB_HOST", "")
+		t.Setenv("JWT_SECRET", "test-secret")
+		cfg, err := LoadConfig("")
+		if err != nil {
+			t.Fatalf("LoadConfig: %v", err)
+		}
+		if cfg.Database.Host != "localhost" {
+			t.Errorf("database.host = %q, want localhost", cfg.Database.Host)
+		}
+	})
+
+	t.Run("缺少 JWT 密钥", func(t *testing.T) {
+		t.Setenv("DB_HOST", "")
+		t.Setenv("JWT_SECRET", "")
+		// JWT 密钥没有安全的默认值，返回校验错误而不是使用空密钥签发 token
+		_, err := LoadConfig("")
+		if err == nil {
+			t.Fatal("LoadConfig succeeded without jwt.secret")
+		}
+		if !strings.Contains(err.Error(), "jwt.secret") || strings.Contains(err.Error(), "database.host") {
+			t.Errorf("error %q, want only jwt.secret to be reported", err)
+		}
+	})
+}
+
+func TestDatabasePoolConfig(t *testing.T) {
+	t.Run("默认值", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig(writeConfig(t, ""))
+		if err != nil {
+			t.Fatalf("LoadConfig: %v", err)
+		}
+		db := cfg.Database
+		if db.GetMaxOpenConns() != 25 || db.GetMaxIdleConns() != 10 || db.GetConnMaxLifetime() != 5*time.Minute {
+			t.Errorf("pool = (%d, %d, %v), want (25, 10, 5m)", db.GetMaxOpenConns(), db.GetMaxIdleConns(), db.GetConnMaxLifetime())
+		}
+	})
+
+	t.Run("环境变量覆盖", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("DB_MAX_OPEN", "50")
+		t.Setenv("DB_MAX_IDLE", "20")
+		t.Setenv("DB_CONN_LIFETIME", "60")
+		cfg, err := LoadConfig(writeConfig(t, "database:\n  max_open_conns: 30\n"))
+		if err != nil {
+			t.Fatalf("LoadConfig: %v", err)
+		}
+		db := cfg.Database
+		if db.GetMaxOpenConns() != 50 || db.GetMaxIdleConns() != 20 || db.GetConnMaxLifetime() != time.Minute {
+			t.Errorf("pool = (%d, %d, %v), want (50, 20, 1m)", db.GetMaxOpenConns(), db.GetMaxIdleConns(), db.GetConnMaxLifetime())
+		}
+	})
+}
+
+// validConfig 返回通过校验的配置，测试在此基础上修改单个字段
+func validConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.JWT.Secret = "test-secret"
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantKey string // 为空表示校验通过
+	}{
+		{"有效配置", func(*Config) {}, ""},
+		{"数据库地址为空", func(c *Config) { c.Database.Host = "" }, "database.host"},
+		{"数据库端口为 0", func(c *Config) { c.Database.Port = 0 }, "database.port"},
+		{"数据库端口超出范围", func(c *Config) { c.Database.Port = 65536 }, "database.port"},
+		{"数据库端口上限", func(c *Config) { c.Database.Port = 65535 }, ""},
+		{"JWT 密钥为空", func(c *Config) { c.JWT.Secret = "" }, "jwt.secret"},
+		{"JWT 密钥只有空白", func(c *Config) { c.JWT.Secret = "  " }, "jwt.secret"},
+		{"服务端口为负数", func(c *Config) { c.App.Port = -1 }, "app.port"},
+		{"服务端口超出范围", func(c *Config) { c.App.Port = 70000 }, "app.port"},
+		{"服务端口下限", func(c *Config) { c.App.Port = 1 }, ""},
+		{"未知运行模式", func(c *Config) { c.App.Mode = "production" }, "app.mode"},
+		{"release 模式", func(c *Config) { c.App.Mode = "release" }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantKey == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantKey) {
+				t.Errorf("Validate() = %v, want error mentioning %s", err, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.Host = ""
+	cfg.Database.Port = 0
+	cfg.JWT.Secret = ""
+	cfg.App.Port = 0
+	cfg.App.Mode = "unknown"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate succeeded")
+	}
+	// 一次返回全部错误，而不是在第一个错误处停止
+	for _, key := range []string{"database.host", "database.port", "jwt.secret", "app.port", "app.mode"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error %q does not mention %s", err, key)
+		}
+	}
+}
+
+func TestGetDSN(t *testing.T) {
+	db := DatabaseConfig{
+		Driver:   DriverPostgres,
+		Host:     "db.local",
+		Port:     5432,
+		User:     "gojet",
+		Password: "secret",
+		DBName:   "app",
+		SSLMode:  "require",
+	}
+	want := "host=db.local user=gojet password=secret dbname=app port=5432 sslmode=require"
+	if got := db.GetDSN(); got != want {
+		t.Errorf("GetDSN() = %q, want %q", got, want)
+	}
+	if got := db.GetDSNRedacted(); strings.Contains(got, "secret") || !strings.Contains(got, "[REDACTED]") {
+		t.Errorf("GetDSNRedacted() = %q", got)
+	}
+}
+
+func TestGetDSNMySQL(t *testing.T) {
+	tests := []struct {
+		name, password, sslMode, wantTLS string
+	}{
+		{"普通密码", "secret", "disable", ""},
+		{"密码包含特殊字符", "p@ss/w:rd", "disable", ""},
+		{"要求加密", "secret", "require", "skip-verify"},
+		{"校验证书", "secret", "verify-full", "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := DatabaseConfig{
+				Driver:   DriverMySQL,
+				Host:     "db.local",
+				Port:     3306,
+				User:     "gojet",
+				Password: tt.password,
+				DBName:   "app",
+				SSLMode:  tt.sslMode,
+			}
+			dsn := db.GetDSN()
+			if !strings.HasPrefix(dsn, "gojet:"+tt.password+"@tcp(db.local:3306)/app?") {
+				t.Errorf("GetDSN() = %q, want user:pass@tcp(host:port)/dbname?...", dsn)
+			}
+			// 使用驱动解析，确认驱动实际读到的参数与配置一致
+			mc, err := mysql.ParseDSN(dsn)
+			if err != nil {
+				t.Fatalf("ParseDSN(%q): %v", dsn, err)
+			}
+			if mc.Passwd != tt.password || mc.Addr != "db.local:3306" || mc.DBName != "app" {
+				t.Errorf("parsed = {Passwd: %q, Addr: %q, DBName: %q}", mc.Passwd, mc.Addr, mc.DBName)
+			}
+			if mc.Params["charset"] != "utf8mb4" {
+				t.Errorf("charset = %q, want utf8mb4", mc.Params["charset"])
+			}
+			if !mc.ParseTime || !mc.ClientFoundRows {
+				t.Errorf("ParseTime = %v, ClientFoundRows = %v, want both true", mc.ParseTime, mc.ClientFoundRows)
+			}
+			if mc.TLSConfig != tt.wantTLS {
+				t.Errorf("tls = %q, want %q", mc.TLSConfig, tt.wantTLS)
+			}
+			if redacted := db.GetDSNRedacted(); strings.Contains(redacted, tt.password) {
+				t.Errorf("GetDSNRedacted() = %q leaks password", redacted)
+			}
+		})
+	}
+}
+
+func TestDatabaseDriverEnvOverride(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("DB_DRIVER", "MySQL")
+	cfg, err := LoadConfig(writeConfig(t, ""))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Database.Driver != DriverMySQL {
+		t.Errorf("database.driver = %q, want %q", cfg.Database.Driver, DriverMySQL)
+	}
+
+	t.Setenv("DB_DRIVER", "sqlite")
+	if _, err := LoadConfig(writeConfig(t, "")); err == nil || !strings.Contains(err.Error(), "database.driver") {
+		t.Errorf("LoadConfig with DB_DRIVER=sqlite: err = %v, want database.driver error", err)
+	}
+}
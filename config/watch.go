@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce 合并编辑器保存文件时产生的多次事件
+const watchDebounce = 200 * time.Millisecond
+
+// Watch 监听配置文件变化，文件修改后重新加载配置，内容有变化时以新配置调用 onChange
+// 加载或校验失败时保留旧配置并记录错误；数据库配置需要重启才能生效，变更会被忽略并输出警告
+// 返回的 stop 用于停止监听，可重复调用
+func Watch(path string, onChange func(*Config)) (stop func(), err error) {
+	current, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("创建配置文件监听失败: %w", err)
+	}
+	// 监听所在目录而不是文件本身：编辑器和 Kubernetes ConfigMap 通过替换文件的方式更新，
+	// 直接监听文件会在替换后失效
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("监听配置目录失败: %w", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if relevant(event, path) {
+					debounce = time.After(watchDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("监听配置文件出错", "error", err)
+			case <-debounce:
+				debounce = nil
+				current = reload(path, current, onChange)
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			watcher.Close()
+			<-done
+		})
+	}
+	return stop, nil
+}
+
+// relevant 判断事件是否可能改变配置文件内容
+func relevant(event fsnotify.Event, path string) bool {
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+		return false
+	}
+	// ConfigMap 更新时替换的是目录中的 ..data 符号链接
+	name := filepath.Base(event.Name)
+	return name == filepath.Base(path) || name == "..data"
+}
+
+// reload 重新加载配置并在有变化时通知调用方，返回之后用于比较的配置
+func reload(path string, current *Config, onChange func(*Config)) *Config {
+	next, err := LoadConfig(path)
+	if err != nil {
+		slog.Error("重新加载配置失败，继续使用旧配置", "path", path, "error", err)
+		return current
+	}
+
+	if !reflect.DeepEqual(next.Database, current.Database) {
+		slog.Warn("数据库配置变更需要重启服务才能生效，已忽略", "path", path)
+		next.Database = current.Database
+	}
+	if reflect.DeepEqual(next, current) {
+		return current
+	}
+
+	slog.Info("配置文件已变更", "path", path)
+	onChange(next)
+	return next
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitChange 等待 onChange 被调用，超时返回 nil
+func waitChange(changes <-chan *Config, timeout time.Duration) *Config {
+	select {
+	case cfg := <-changes:
+		return cfg
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(path, content string) error
+	}{
+		{"直接写入", func(path, content string) error {
+			return os.WriteFile(path, []byte(content), 0o600)
+		}},
+		// 编辑器与 ConfigMap 通过写入临时文件再替换的方式更新
+		{"替换文件", func(path, content string) error {
+			tmp := filepath.Join(filepath.Dir(path), "config.yaml.tmp")
+			if err := os.WriteFile(tmp, []byte(content), 0o600); err != nil {
+				return err
+			}
+			return os.Rename(tmp, path)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			path := writeConfig(t, "logging:\n  level: info\n")
+
+			changes := make(chan *Config, 1)
+			stop, err := Watch(path, func(cfg *Config) { changes <- cfg })
+			if err != nil {
+				t.Fatalf("Watch: %v", err)
+			}
+			defer stop()
+
+			if err := tt.write(path, "logging:\n  level: debug\n"); err != nil {
+				t.Fatal(err)
+			}
+			cfg := waitChange(changes, 5*time.Second)
+			if cfg == nil {
+				t.Fatal("onChange not called within 5s")
+			}
+			if cfg.Logging.Level != "debug" {
+				t.Errorf("logging.level = %q, want debug", cfg.Logging.Level)
+			}
+		})
+	}
+}
+
+func TestWatchIgnoresUnchangedAndInvalid(t *testing.T) {
+	setRequiredEnv(t)
+	path := writeConfig(t, "logging:\n  level: info\n")
+
+	changes := make(chan *Config, 1)
+	stop, err := Watch(path, func(cfg *Config) { changes <- cfg })
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer stop()
+
+	for _, content := range []string{
+		"logging:\n  level: info\n# 只修改注释\n",
+		"logging: [",                // YAML 格式错误，保留旧配置
+		"database:\n  port: 5433\n", // 数据库配置需要重启才能生效
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if cfg := waitChange(changes, 3*watchDebounce); cfg != nil {
+			t.Errorf("onChange called for %q", content)
+		}
+	}
+}
+
+func TestWatchStop(t *testing.T) {
+	setRequiredEnv(t)
+	path := writeConfig(t, "logging:\n  level: info\n")
+
+	changes := make(chan *Config, 1)
+	stop, err := Watch(path, func(cfg *Config) { changes <- cfg })
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	stop()
+	stop() // 可重复调用
+
+	if err := os.WriteFile(path, []byte("logging:\n  level: debug\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := waitChange(changes, 3*watchDebounce); cfg != nil {
+		t.Error("onChange called after stop")
+	}
+}
@@ -0,0 +1,72 @@
+package dao
+
+import (
+	"context"
+
+	"gojet/cache"
+	"gojet/models"
+	"gojet/service"
+)
+
+// 编译期检查 CachedUserRepository 是否实现了 service 层依赖的接口
+var _ service.UserRepository = (*CachedUserRepository)(nil)
+
+// CachedUserRepository 为用户仓库增加按 ID 的读缓存
+// GetByID 优先读取缓存，修改、删除用户后删除对应缓存；其他方法直接调用被包装的仓库
+// GetByIDFromPrimary 用于需要读到最新数据的场景，不经过缓存
+type CachedUserRepository struct {
+	service.UserRepository
+	cache cache.UserCache
+}
+
+// NewCachedUserRepository 使用 c 包装用户仓库，c 为 nil 时不缓存
+func NewCachedUserRepository(repo service.UserRepository, c cache.UserCache) *CachedUserRepository {
+	if c == nil {
+		c = cache.NoopUserCache{}
+	}
+	return &CachedUserRepository{UserRepository: repo, cache: c}
+}
+
+// GetByID 根据 ID 获取用户，缓存未命中时查询数据库并写入缓存
+func (r *CachedUserRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	if user, ok := r.cache.Get(id); ok {
+		return user, nil
+	}
+	user, err := r.UserRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Set(user)
+	return user, nil
+}
+
+// Update 更新用户指定列并删除缓存
+// 更新失败时数据库是否已修改无法确定，删除缓存总是安全的，因此不论结果都删除
+func (r *CachedUserRepository) Update(ctx context.Context, id uint, fields map[string]any) error {
+	err := r.UserRepository.Update(ctx, id, fields)
+	r.cache.Delete(id)
+	return err
+}
+
+// SetStatus 修改用户账号状态并删除缓存
+func (r *CachedUserRepository) SetStatus(ctx context.Context, id uint, status string) error {
+	err := r.UserRepository.SetStatus(ctx, id, status)
+	r.cache.Delete(id)
+	return err
+}
+
+// Delete 删除用户并删除缓存
+func (r *CachedUserRepository) Delete(ctx context.Context, id uint) error {
+	err := r.UserRepository.Delete(ctx, id)
+	r.cache.Delete(id)
+	return err
+}
+
+// DeleteBatch 批量删除用户，并删除所有请求 ID 的缓存
+func (r *CachedUserRepository) DeleteBatch(ctx context.Context, ids []uint) ([]uint, error) {
+	deleted, err := r.UserRepository.DeleteBatch(ctx, ids)
+	for _, id := range ids {
+		r.cache.Delete(id)
+	}
+	return deleted, err
+}
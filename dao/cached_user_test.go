@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gojet/cache"
+	"gojet/models"
+	"gojet/service/servicetest"
+	"gojet/util/apperror"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newCachedRepo 使用内存仓库与内存 Redis 创建带缓存的用户仓库
+func newCachedRepo(t *testing.T, users ...*models.User) (*CachedUserRepository, *servicetest.UserRepository, *cache.RedisUserCache) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	c, err := cache.NewRedisUserCache(mr.Addr(), "", 0, time.Minute)
+	if err != nil {
+		t.Fatalf("NewRedisUserCache: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	repo := servicetest.NewUserRepository(users...)
+	return NewCachedUserRepository(repo, c), repo, c
+}
+
+func TestCachedUserRepositoryGetByID(t *testing.T) {
+	ctx := context.Background()
+	cached, repo, c := newCachedRepo(t, &models.User{ID: 1, Username: "alice", NickName: "Alice"})
+
+	// 第一次未命中，查询仓库并写入缓存
+	for range 3 {
+		user, err := cached.GetByID(ctx, 1)
+		if err != nil || user.Username != "alice" {
+			t.Fatalf("GetByID(1) = %+v, %v", user, err)
+		}
+	}
+	if n := repo.Calls("GetByID"); n != 1 {
+		t.Errorf("repository GetByID called %d times, want 1", n)
+	}
+	if _, ok := c.Get(1); !ok {
+		t.Error("user not cached after miss")
+	}
+
+	// 不存在的用户不写入缓存，每次都查询仓库
+	for range 2 {
+		_, err := cached.GetByID(ctx, 2)
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Code != 404 {
+			t.Fatalf("GetByID(2) err = %v, want 404", err)
+		}
+	}
+	if n := repo.Calls("GetByID"); n != 3 {
+		t.Errorf("repository GetByID called %d times, want 3", n)
+	}
+}
+
+func TestCachedUserRepositoryInvalidates(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		write func(r *CachedUserRepository) error
+	}{
+		{"Update", func(r *CachedUserRepository) error {
+			return r.Update(ctx, 1, map[string]any{"nick_name": "Alicia"})
+		}},
+		{"SetStatus", func(r *CachedUserRepository) error {
+			return r.SetStatus(ctx, 1, models.StatusInactive)
+		}},
+		{"Delete", func(r *CachedUserRepository) error {
+			return r.Delete(ctx, 1)
+		}},
+		{"DeleteBatch", func(r *CachedUserRepository) error {
+			_, err := r.DeleteBatch(ctx, []uint{1, 3})
+			return err
+		}},
+		// 写入失败时同样删除缓存，下次读取以数据库为准
+		{"Update 失败", func(r *CachedUserRepository) error {
+			r.UserRepository.(*servicetest.UserRepository).FailOn("Update", errors.New("connection reset"))
+			if err := r.Update(ctx, 1, map[string]any{"nick_name": "Alicia"}); err == nil {
+				return errors.New("Update succeeded")
+			}
+			return nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cached, _, c := newCachedRepo(t,
+				&models.User{ID: 1, Username: "alice", NickName: "Alice"},
+				&models.User{ID: 2, Username: "bob", NickName: "Bob"},
+			)
+			for _, id := range []uint{1, 2} {
+				if _, err := cached.GetByID(ctx, id); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := tt.write(cached); err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if _, ok := c.Get(1); ok {
+				t.Error("cache not invalidated for user 1")
+			}
+			if _, ok := c.Get(2); !ok {
+				t.Error("cache for unrelated user 2 invalidated")
+			}
+		})
+	}
+}
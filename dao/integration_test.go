@@ -0,0 +1,153 @@
+//go:build integration
+
+package dao_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"gojet/dao"
+	"gojet/models"
+	"gojet/service"
+	"gojet/util/apperror"
+	"gojet/util/messaging"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// openTestDB 连接 TEST_DATABASE_DSN 指定的测试数据库并清空 users 表，未设置时跳过
+// TEST_DATABASE_DRIVER 取值 postgres（默认）或 mysql
+// 测试会删除数据，不要指向开发或生产数据库
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("未设置 TEST_DATABASE_DSN，跳过集成测试")
+	}
+	dialector := postgres.Open(dsn)
+	if os.Getenv("TEST_DATABASE_DRIVER") == "mysql" {
+		dialector = mysql.Open(dsn)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("连接测试数据库失败: %v", err)
+	}
+	if err := dao.Migrate(db); err != nil {
+		t.Fatalf("迁移失败: %v", err)
+	}
+	if err := db.Exec("DELETE FROM refresh_tokens").Error; err != nil {
+		t.Fatalf("清空 refresh_tokens 失败: %v", err)
+	}
+	if err := db.Exec("DELETE FROM password_reset_tokens").Error; err != nil {
+		t.Fatalf("清空 password_reset_tokens 失败: %v", err)
+	}
+	if err := db.Exec("DELETE FROM users").Error; err != nil {
+		t.Fatalf("清空 users 失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestCreateInitialDataConcurrentInstances(t *testing.T) {
+	db := openTestDB(t)
+	if err := models.SetBCryptCost(bcrypt.MinCost); err != nil {
+		t.Fatal(err)
+	}
+	service.InitService(dao.NewUserRepository(db), messaging.NoopPublisher{})
+
+	// 两个实例同时启动，只有一个能获得锁并插入初始数据
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for range 2 {
+		wg.Go(func() {
+			errs <- service.CreateInitialData(context.Background())
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("CreateInitialData: %v", err)
+		}
+	}
+
+	var count int64
+	if err := db.Model(&models.User{}).Where("username = ?", "包子").Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("seed user inserted %d times, want 1", count)
+	}
+}
+
+func TestCreateBatchInsertsAllRows(t *testing.T) {
+	db := openTestDB(t)
+
+	const total = 250
+	users := make([]*models.User, total)
+	for i := range users {
+		users[i] = &models.User{
+			Username: fmt.Sprintf("batch%d", i),
+			NickName: fmt.Sprintf("Batch %d", i),
+			Email:    fmt.Sprintf("batch%d@example.com", i),
+			Password: "hash",
+		}
+	}
+	if err := dao.NewUserRepository(db).CreateBatch(context.Background(), users); err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.User{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != total {
+		t.Errorf("inserted %d rows, want %d", count, total)
+	}
+}
+
+func TestUserIndexesCreated(t *testing.T) {
+	db := openTestDB(t)
+
+	migrator := db.Migrator()
+	for _, name := range []string{"idx_users_username", "idx_users_email", "idx_users_status", "idx_users_created_at", "idx_users_deleted_at"} {
+		if !migrator.HasIndex(&models.User{}, name) {
+			t.Errorf("索引 %s 未创建", name)
+		}
+	}
+}
+
+func TestUserUniqueConstraints(t *testing.T) {
+	db := openTestDB(t)
+	repo := dao.NewUserRepository(db)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.User{Username: "alice", NickName: "Alice", Email: "alice@example.com", Password: "hash"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	tests := []struct {
+		name string
+		user *models.User
+	}{
+		{"用户名重复", &models.User{Username: "alice", NickName: "A", Email: "other@example.com", Password: "hash"}},
+		{"邮箱重复", &models.User{Username: "other", NickName: "O", Email: "alice@example.com", Password: "hash"}},
+	}
+	for _, tt := range tests {
+		err := repo.Create(ctx, tt.user)
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Code != 409 {
+			t.Errorf("%s: err = %v, want 409", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package dao
+
+import (
+	"fmt"
+
+	"gojet/models"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移 - 先处理表重命名等 AutoMigrate 无法完成的变更，再同步表结构
+func Migrate(db *gorm.DB) error {
+	// "user" 是 SQL 保留字，旧版本使用该表名，需要重命名为 "users"
+	// 通过 Migrator 重命名，由方言负责标识符引号（PostgreSQL 为双引号，MySQL 为反引号）
+	migrator := db.Migrator()
+	if migrator.HasTable("user") && !migrator.HasTable("users") {
+		if err := migrator.RenameTable("user", "users"); err != nil {
+			return fmt.Errorf("重命名用户表失败: %w", err)
+		}
+	}
+
+	// users.role 带默认值 user，AutoMigrate 新增该列时已有用户自动成为普通用户
+	if err := db.AutoMigrate(&models.User{}, &models.RefreshToken{}, &models.PasswordResetToken{}); err != nil {
+		return fmt.Errorf("同步表结构失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gojet/cache"
+	"gojet/models"
+	"gojet/service"
+	"gojet/util/apperror"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 编译期检查 PasswordResetTokenRepository 是否实现了 service 层依赖的接口
+var _ service.PasswordResetTokenRepository = (*PasswordResetTokenRepository)(nil)
+
+type PasswordResetTokenRepository struct {
+	db    *gorm.DB        // GORM 数据库连接实例（主库）
+	cache cache.UserCache // 用户缓存，重置密码后删除对应用户的缓存
+}
+
+// NewPasswordResetTokenRepository 创建密码重置令牌仓库实例
+// 重置密码直接修改 users 表，需传入 CachedUserRepository 使用的同一个缓存，c 为 nil 时不处理缓存
+func NewPasswordResetTokenRepository(db *gorm.DB, c cache.UserCache) *PasswordResetTokenRepository {
+	if c == nil {
+		c = cache.NoopUserCache{}
+	}
+	return &PasswordResetTokenRepository{db: db, cache: c}
+}
+
+// Create 保存新生成的密码重置令牌
+func (r *PasswordResetTokenRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	result := r.db.WithContext(ctx).Create(token)
+	if result.Error != nil {
+		return apperror.Wrap(result.Error, 500, apperror.DBInsertError)
+	}
+	return nil
+}
+
+// ResetPassword 使用未过期、未使用的令牌重置密码，返回令牌所属的用户ID
+// 在同一事务中标记令牌已使用、更新密码并吊销该用户的全部 refresh token；令牌无效时返回 0
+func (r *PasswordResetTokenRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uint, error) {
+	var userID uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// 锁定有效的令牌后再标记已使用，并发请求时同一个令牌只有一个能成功
+		// MySQL 不支持 UPDATE ... RETURNING，因此先 SELECT ... FOR UPDATE 取出令牌
+		var token models.PasswordResetToken
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+			Limit(1).Find(&token)
+		if result.Error != nil {
+			return apperror.Wrap(result.Error, 500, apperror.DBQueryError)
+		}
+		if result.RowsAffected != 1 {
+			return errTokenNotFound
+		}
+
+		result = tx.Model(&token).Where("used_at IS NULL").Update("used_at", now)
+		if result.Error != nil {
+			return apperror.Wrap(result.Error, 500, apperror.DBUpdateError)
+		}
+		if result.RowsAffected != 1 {
+			return errTokenNotFound
+		}
+
+		result = tx.Model(&models.User{}).Where("id = ?", token.UserID).Update("password", passwordHash)
+		if result.Error != nil {
+			return apperror.Wrap(result.Error, 500, apperror.DBUpdateError)
+		}
+		if result.RowsAffected != 1 {
+			// 用户已被删除
+			return errTokenNotFound
+		}
+
+		// 密码重置后已登录的会话不能继续刷新 token
+		result = tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", token.UserID).
+			Update("revoked_at", now)
+		if result.Error != nil {
+			return apperror.Wrap(result.Error, 500, apperror.DBUpdateError)
+		}
+
+		userID = token.UserID
+		return nil
+	})
+	if errors.Is(err, errTokenNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	r.cache.Delete(userID)
+	return userID, nil
+}
+
+// errTokenNotFound 令牌无效，用于回滚事务
+var errTokenNotFound = errors.New("password reset token not found")
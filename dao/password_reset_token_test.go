@@ -0,0 +1,45 @@
+package dao
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"gojet/cache"
+	"gojet/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestResetPasswordInvalidatesUserCache(t *testing.T) {
+	db, mock := newMockDB(t)
+	userCache, err := cache.NewInMemoryUserCache(10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userCache.Set(&models.User{ID: 7, Username: "alice", Password: "old-hash"})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "password_reset_tokens"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token_hash"}).AddRow(1, 7, "token-hash"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "password_reset_tokens" SET "used_at"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "password"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "revoked_at"`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	userID, err := NewPasswordResetTokenRepository(db, userCache).ResetPassword(context.Background(), "token-hash", "new-hash")
+	if err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if userID != 7 {
+		t.Errorf("userID = %d, want 7", userID)
+	}
+	// 缓存中的旧密码哈希必须失效
+	if _, ok := userCache.Get(7); ok {
+		t.Error("user cache not invalidated after password reset")
+	}
+}
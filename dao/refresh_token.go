@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gojet/models"
+	"gojet/service"
+	"gojet/util/apperror"
+
+	"gorm.io/gorm"
+)
+
+// 编译期检查 RefreshTokenRepository 是否实现了 service 层依赖的接口
+var _ service.RefreshTokenRepository = (*RefreshTokenRepository)(nil)
+
+type RefreshTokenRepository struct {
+	db *gorm.DB // GORM 数据库连接实例（主库，refresh token 读写均走主库）
+}
+
+// NewRefreshTokenRepository 创建 refresh token 仓库实例
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create 保存新签发的 refresh token
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	result := r.db.WithContext(ctx).Create(token)
+	if result.Error != nil {
+		return apperror.Wrap(result.Error, 500, apperror.DBInsertError)
+	}
+	return nil
+}
+
+// RevokeAll 吊销用户所有未吊销的 refresh token，用于修改密码后使其他会话失效
+func (r *RefreshTokenRepository) RevokeAll(ctx context.Context, userID uint) error {
+	result := r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return apperror.Wrap(result.Error, 500, apperror.DBUpdateError)
+	}
+	return nil
+}
+
+// Revoke 吊销属于 userID 且未过期、未吊销的 refresh token
+// 通过一条条件 UPDATE 完成校验和吊销，并发刷新时同一个 token 只有一个请求能成功
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, tokenHash string, userID uint) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("token_hash = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", tokenHash, userID, now).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return false, apperror.Wrap(result.Error, 500, apperror.DBUpdateError)
+	}
+	return result.RowsAffected == 1, nil
+}
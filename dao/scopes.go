@@ -0,0 +1,80 @@
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Scope GORM 查询作用域，每个过滤条件对应一个作用域，可自由组合
+type Scope = func(*gorm.DB) *gorm.DB
+
+// UsernameScope 按用户名模糊匹配（忽略大小写）
+func UsernameScope(username string) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return containsFold(db, "username", username)
+	}
+}
+
+// NickNameScope 按昵称模糊匹配（忽略大小写）
+func NickNameScope(nickName string) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return containsFold(db, "nick_name", nickName)
+	}
+}
+
+// EmailScope 按邮箱精确匹配（忽略大小写）
+func EmailScope(email string) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("LOWER(email) = LOWER(?)", email)
+	}
+}
+
+// StatusScope 按账号状态精确匹配
+func StatusScope(status string) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ?", status)
+	}
+}
+
+// CreatedAfterScope 创建时间不早于 t
+func CreatedAfterScope(t time.Time) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("created_at >= ?", t)
+	}
+}
+
+// CreatedBeforeScope 创建时间早于 t
+func CreatedBeforeScope(t time.Time) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("created_at < ?", t)
+	}
+}
+
+// PaginateScope 分页，page 从 1 开始；调用方需保证 page、size 均为正数
+func PaginateScope(page, size int) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset((page - 1) * size).Limit(size)
+	}
+}
+
+// containsFold 字段包含 value（忽略大小写），column 必须是代码中的固定列名
+// PostgreSQL 使用 ILIKE；MySQL 不支持 ILIKE，两边转为小写后使用 LIKE，不依赖列的排序规则
+func containsFold(db *gorm.DB, column, value string) *gorm.DB {
+	pattern := "%" + escapeLike(value) + "%"
+	if isMySQL(db) {
+		return db.Where("LOWER("+column+") LIKE LOWER(?)", pattern)
+	}
+	return db.Where(column+" ILIKE ?", pattern)
+}
+
+// isMySQL 判断连接是否为 MySQL/MariaDB，用于少数需要区分方言的 SQL
+func isMySQL(db *gorm.DB) bool {
+	return db.Dialector.Name() == "mysql"
+}
+
+// escapeLike 转义 LIKE 通配符，避免用户输入的 % 和 _ 被当作通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// tracer DAO 层使用的 tracer，未启用链路追踪时为空实现
+var tracer = otel.Tracer("gojet/dao")
+
+// spanKey 在 gorm.Statement 中保存当前 span 的键
+const spanKey = "gojet:span"
+
+// TracingPlugin GORM 链路追踪插件 - 为每条 SQL 语句创建子 span
+// 只在 context 中已有 span（即由请求链路发起）时创建，启动迁移等后台查询不产生孤立的 span
+type TracingPlugin struct{}
+
+// Name 插件名称
+func (TracingPlugin) Name() string {
+	return "gojet:tracing"
+}
+
+// Initialize 在 GORM 各类操作前后注册回调
+func (TracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", startSpan("gorm.Create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", startSpan("gorm.Query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", startSpan("gorm.Update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startSpan("gorm.Delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", startSpan("gorm.Row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startSpan("gorm.Raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	)
+}
+
+// startSpan 返回在语句执行前创建 span 的回调
+func startSpan(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			return
+		}
+		_, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// endSpan 在语句执行后记录 SQL（参数以占位符表示，不包含用户数据）与错误并结束 span
+func endSpan(db *gorm.DB) {
+	v, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.system.name", db.Dialector.Name()),
+		attribute.String("db.collection.name", db.Statement.Table),
+		attribute.String("db.query.text", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
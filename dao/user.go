@@ -1,56 +1,164 @@
 package dao
 
 import (
+	"context"
 	"errors"
+	"strconv"
+	"strings"
 
 	"gojet/models"
+	"gojet/service"
 	"gojet/util/apperror"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// 编译期检查 UserRepository 是否实现了 service 层依赖的接口
+var _ service.UserRepository = (*UserRepository)(nil)
+
 type UserRepository struct {
-	db *gorm.DB // GORM 数据库连接实例
+	db      *gorm.DB // GORM 数据库连接实例（主库，负责写操作）
+	replica *gorm.DB // 只读副本连接实例（负责读操作）
 }
 
-// NewUserRepository 创建用户仓库实例
+// NewUserRepository 创建用户仓库实例，读写均使用同一连接
 func NewUserRepository(db *gorm.DB) *UserRepository {
-	return &UserRepository{db: db}
+	return &UserRepository{db: db, replica: db}
+}
+
+// NewUserRepositoryWithReplica 创建读写分离的用户仓库实例
+// 读操作走只读副本，写操作走主库；replica 为 nil 时读操作回落到主库
+func NewUserRepositoryWithReplica(primary, replica *gorm.DB) *UserRepository {
+	if replica == nil {
+		replica = primary
+	}
+	return &UserRepository{db: primary, replica: replica}
+}
+
+// WithAdvisoryLock 在会话级锁保护下执行 fn：PostgreSQL 使用 advisory lock，MySQL 使用 GET_LOCK
+// 加锁与解锁必须使用同一连接，因此通过 Connection 固定一个连接；
+// 锁已被其他实例持有时不执行 fn，并返回 acquired=false
+func (r *UserRepository) WithAdvisoryLock(ctx context.Context, lockID int64, fn func() error) (acquired bool, err error) {
+	lockSQL, unlockSQL := "SELECT pg_try_advisory_lock(?)", "SELECT pg_advisory_unlock(?)"
+	var key any = lockID
+	if isMySQL(r.db) {
+		// GET_LOCK 超时为 0 时立即返回，获得锁返回 1，否则返回 0 或 NULL
+		lockSQL, unlockSQL = "SELECT COALESCE(GET_LOCK(?, 0), 0) = 1", "SELECT RELEASE_LOCK(?)"
+		key = "gojet:" + strconv.FormatInt(lockID, 10)
+	}
+
+	err = r.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Raw(lockSQL, key).Scan(&acquired).Error; err != nil {
+			return apperror.Wrap(err, 500, apperror.DBQueryError)
+		}
+		if !acquired {
+			return nil
+		}
+		defer conn.Exec(unlockSQL, key)
+
+		return fn()
+	})
+	return acquired, err
 }
 
 // Create 创建用户
-func (r *UserRepository) Create(user *models.User) error {
-	result := r.db.Create(user)
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	result := r.db.WithContext(ctx).Create(user)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return apperror.Wrap(result.Error, 409, apperror.UserDuplicate)
+	}
 	if result.Error != nil {
 		return apperror.Wrap(result.Error, 500, apperror.DBInsertError)
 	}
 	return nil
 }
 
-// CreateBatch 批量创建用户
-func (r *UserRepository) CreateBatch(users []*models.User) error {
-	result := r.db.CreateInBatches(users, len(users))
+// createBatchSize 批量插入时每条 INSERT 语句包含的行数，避免超过 PostgreSQL 单条语句 65535 个参数的限制
+const createBatchSize = 100
+
+// CreateBatch 批量创建用户，按 createBatchSize 分批插入
+func (r *UserRepository) CreateBatch(ctx context.Context, users []*models.User) error {
+	result := r.db.WithContext(ctx).CreateInBatches(users, createBatchSize)
 	if result.Error != nil {
 		return apperror.Wrap(result.Error, 500, apperror.DBInsertError)
 	}
 	return nil
 }
 
-// GetAll 获取所有用户
-func (r *UserRepository) GetAll() ([]*models.User, error) {
+// GetAll 分页获取所有用户，同时返回用户总数
+func (r *UserRepository) GetAll(ctx context.Context, page, size int) ([]*models.User, int64, error) {
+	return r.GetFiltered(ctx, models.UserFilter{}, page, size)
+}
+
+// ExistsByEmail 判断邮箱是否已被使用（忽略大小写）
+// 用于写入前的校验，查询主库以避免只读副本复制延迟导致漏判
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&models.User{}).Scopes(EmailScope(email)).Limit(1).Count(&count)
+	if result.Error != nil {
+		return false, apperror.Wrap(result.Error, 500, apperror.DBQueryError)
+	}
+	return count > 0, nil
+}
+
+// GetFiltered 按过滤条件分页获取用户，同时返回满足条件的用户总数
+// 只应用已设置的条件；新增过滤条件只需增加对应的作用域
+func (r *UserRepository) GetFiltered(ctx context.Context, filter models.UserFilter, page, size int) ([]*models.User, int64, error) {
+	var scopes []Scope
+	if filter.Username != "" {
+		scopes = append(scopes, UsernameScope(filter.Username))
+	}
+	if filter.NickName != "" {
+		scopes = append(scopes, NickNameScope(filter.NickName))
+	}
+	if filter.Email != "" {
+		scopes = append(scopes, EmailScope(filter.Email))
+	}
+	if filter.Status != "" {
+		scopes = append(scopes, StatusScope(filter.Status))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		scopes = append(scopes, CreatedAfterScope(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		scopes = append(scopes, CreatedBeforeScope(filter.CreatedBefore))
+	}
+
+	// 新建 Session，使计数和分页查询可以复用同一组过滤条件
+	query := r.replica.WithContext(ctx).Model(&models.User{}).Scopes(scopes...).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, apperror.Wrap(err, 500, apperror.DBQueryError)
+	}
+
 	var users []*models.User
-	// GORM 默认不会查询软删除的记录
-	result := r.db.Find(&users)
+	// 按 ID 排序保证分页结果稳定
+	if err := query.Scopes(PaginateScope(page, size)).Order("id").Find(&users).Error; err != nil {
+		return nil, 0, apperror.Wrap(err, 500, apperror.DBQueryError)
+	}
+	return users, total, nil
+}
+
+// GetByID 根据 ID 获取用户
+func (r *UserRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	result := r.replica.WithContext(ctx).First(&user, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, apperror.New(404, apperror.RecordNotFound)
+	}
 	if result.Error != nil {
 		return nil, apperror.Wrap(result.Error, 500, apperror.DBQueryError)
 	}
-	return users, nil
+	return &user, nil
 }
 
-// GetByID 根据 ID 获取用户
-func (r *UserRepository) GetByID(id uint) (*models.User, error) {
+// GetByIDFromPrimary 根据 ID 从主库获取用户
+// 用于写入前的读取（例如校验原密码）及写入后返回最新数据，避免只读副本复制延迟读到旧值
+func (r *UserRepository) GetByIDFromPrimary(ctx context.Context, id uint) (*models.User, error) {
 	var user models.User
-	result := r.db.First(&user, id)
+	result := r.db.WithContext(ctx).First(&user, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, apperror.New(404, apperror.RecordNotFound)
 	}
@@ -61,9 +169,9 @@ func (r *UserRepository) GetByID(id uint) (*models.User, error) {
 }
 
 // GetUserByUserName 根据用户名获取用户
-func (r *UserRepository) GetUserByUserName(username string) (*models.User, error) {
+func (r *UserRepository) GetUserByUserName(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
-	result := r.db.Where("username = ?", username).First(&user)
+	result := r.replica.WithContext(ctx).Where("username = ?", username).First(&user)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, apperror.New(404, apperror.RecordNotFound)
 	}
@@ -73,20 +181,146 @@ func (r *UserRepository) GetUserByUserName(username string) (*models.User, error
 	return &user, nil
 }
 
-// Update 更新用户 - 保存用户信息到数据库
-func (r *UserRepository) Update(user *models.User) error {
-	result := r.db.Save(user)
+// searchLimit 搜索结果最大条数
+const searchLimit = 100
+
+// searchColumns 允许搜索的字段，列名只能取自该白名单，不能拼接用户输入
+var searchColumns = map[string]string{
+	"username":  "username",
+	"nick_name": "nick_name",
+	"email":     "email",
+}
+
+// Search 按指定字段模糊搜索用户（忽略大小写），最多返回 searchLimit 条
+func (r *UserRepository) Search(ctx context.Context, query string, field string) ([]*models.User, error) {
+	column, ok := searchColumns[field]
+	if !ok {
+		return nil, apperror.New(400, apperror.InvalidSearchField)
+	}
+
+	var users []*models.User
+	result := containsFold(r.replica.WithContext(ctx), column, query).Order("id").Limit(searchLimit).Find(&users)
+	if result.Error != nil {
+		return nil, apperror.Wrap(result.Error, 500, apperror.DBQueryError)
+	}
+	return users, nil
+}
+
+// GetByEmail 根据邮箱（忽略大小写）获取用户，查询主库
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	result := r.db.WithContext(ctx).Scopes(EmailScope(email)).First(&user)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, apperror.New(404, apperror.RecordNotFound)
+	}
+	if result.Error != nil {
+		return nil, apperror.Wrap(result.Error, 500, apperror.DBQueryError)
+	}
+	return &user, nil
+}
+
+// GetByEmailOrUsername 根据用户名或邮箱（忽略大小写，与 EmailScope 一致）获取用户，用于登录
+// 查询主库，避免复制延迟导致刚注册的用户无法登录
+func (r *UserRepository) GetByEmailOrUsername(ctx context.Context, value string) (*models.User, error) {
+	var user models.User
+	result := r.db.WithContext(ctx).Where("username = ? OR LOWER(email) = LOWER(?)", value, value).First(&user)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, apperror.New(404, apperror.RecordNotFound)
+	}
+	if result.Error != nil {
+		return nil, apperror.Wrap(result.Error, 500, apperror.DBQueryError)
+	}
+	return &user, nil
+}
+
+// FindEmailDuplicates 查找忽略大小写后邮箱重复的用户，不包括已删除的用户
+// 原生 SQL 不会自动添加软删除条件，需要手动过滤 deleted_at
+func (r *UserRepository) FindEmailDuplicates(ctx context.Context) ([]models.DuplicateGroup, error) {
+	var rows []struct {
+		Email   string
+		UserIDs string
+	}
+	aggregate := "STRING_AGG(id::text, ',' ORDER BY id)"
+	if isMySQL(r.replica) {
+		aggregate = "GROUP_CONCAT(id ORDER BY id SEPARATOR ',')"
+	}
+	result := r.replica.WithContext(ctx).Raw(`SELECT LOWER(email) AS email, ` + aggregate + ` AS user_ids
+		FROM users WHERE deleted_at IS NULL GROUP BY LOWER(email) HAVING COUNT(*) > 1 ORDER BY email`).Scan(&rows)
+	if result.Error != nil {
+		return nil, apperror.Wrap(result.Error, 500, apperror.DBQueryError)
+	}
+
+	groups := make([]models.DuplicateGroup, 0, len(rows))
+	for _, row := range rows {
+		group := models.DuplicateGroup{Email: row.Email}
+		for _, v := range strings.Split(row.UserIDs, ",") {
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, apperror.Wrap(err, 500, apperror.DBQueryError)
+			}
+			group.UserIDs = append(group.UserIDs, uint(id))
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// Update 更新用户的指定字段，fields 的键为列名
+// 只写入变化的列，不会用读取时的旧值覆盖其他列（例如并发修改的状态、角色或密码）
+func (r *UserRepository) Update(ctx context.Context, id uint, fields map[string]any) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(fields)
+	if result.Error != nil {
+		return apperror.Wrap(result.Error, 500, apperror.DBUpdateError)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.New(404, apperror.RecordNotFound)
+	}
+	return nil
+}
+
+// SetStatus 修改用户账号状态
+func (r *UserRepository) SetStatus(ctx context.Context, id uint, status string) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("status", status)
 	if result.Error != nil {
 		return apperror.Wrap(result.Error, 500, apperror.DBUpdateError)
 	}
+	if result.RowsAffected == 0 {
+		return apperror.New(404, apperror.RecordNotFound)
+	}
 	return nil
 }
 
-// Delete 删除用户 - 软删除指定 ID 的用户
-func (r *UserRepository) Delete(id uint) error {
-	result := r.db.Delete(&models.User{}, id)
+// Delete 删除用户 - 软删除指定 ID 的用户，已删除的用户返回 404
+func (r *UserRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.User{}, id)
 	if result.Error != nil {
 		return apperror.Wrap(result.Error, 500, apperror.DBDeleteError)
 	}
+	if result.RowsAffected == 0 {
+		return apperror.New(404, apperror.RecordNotFound)
+	}
 	return nil
 }
+
+// DeleteBatch 在同一事务中批量软删除用户，返回实际删除的用户 ID，不存在或已删除的 ID 会被忽略
+// 先锁定存在的记录再删除，调用方据此区分已删除与不存在的 ID
+func (r *UserRepository) DeleteBatch(ctx context.Context, ids []uint) ([]uint, error) {
+	var deleted []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Order("id").
+			Pluck("id", &deleted).Error; err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		return tx.Where("id IN ?", deleted).Delete(&models.User{}).Error
+	})
+	if err != nil {
+		return nil, apperror.Wrap(err, 500, apperror.DBDeleteError)
+	}
+	return deleted, nil
+}
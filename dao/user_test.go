@@ -0,0 +1,325 @@
+package dao
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"gojet/models"
+	"gojet/util/apperror"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newMockDB 创建基于 sqlmock 的 PostgreSQL GORM 连接，测试结束时校验所有期望的 SQL 均已执行
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建 sqlmock 失败: %v", err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("打开 GORM 连接失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("SQL 期望未满足: %v", err)
+		}
+		sqlDB.Close()
+	})
+	return db, mock
+}
+
+var userColumns = []string{"id", "username", "nick_name", "email", "password", "role", "status"}
+
+func TestUserRepositoryReadWriteSplit(t *testing.T) {
+	ctx := context.Background()
+	selectByID := regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1`)
+
+	t.Run("GetByID 读只读副本", func(t *testing.T) {
+		primary, _ := newMockDB(t)
+		replica, replicaMock := newMockDB(t)
+		replicaMock.ExpectQuery(selectByID).WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "alice", "Alice", "alice@example.com", "hash", "user", "active"))
+
+		user, err := NewUserRepositoryWithReplica(primary, replica).GetByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("GetByID 返回错误: %v", err)
+		}
+		if user.Username != "alice" {
+			t.Errorf("Username = %q, want alice", user.Username)
+		}
+	})
+
+	t.Run("GetByIDFromPrimary 读主库", func(t *testing.T) {
+		primary, primaryMock := newMockDB(t)
+		replica, _ := newMockDB(t)
+		primaryMock.ExpectQuery(selectByID).WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "alice", "Alice", "alice@example.com", "hash", "user", "active"))
+
+		if _, err := NewUserRepositoryWithReplica(primary, replica).GetByIDFromPrimary(ctx, 1); err != nil {
+			t.Fatalf("GetByIDFromPrimary 返回错误: %v", err)
+		}
+	})
+
+	t.Run("GetByEmailOrUsername 读主库且邮箱忽略大小写", func(t *testing.T) {
+		primary, primaryMock := newMockDB(t)
+		replica, _ := newMockDB(t)
+		// 刚注册的用户可能尚未复制到只读副本，登录查询必须读主库
+		primaryMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE (username = $1 OR LOWER(email) = LOWER($2)) AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT $3`)).
+			WithArgs("Alice@Example.com", "Alice@Example.com", 1).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "alice", "Alice", "alice@example.com", "hash", "user", "active"))
+
+		user, err := NewUserRepositoryWithReplica(primary, replica).GetByEmailOrUsername(ctx, "Alice@Example.com")
+		if err != nil {
+			t.Fatalf("GetByEmailOrUsername 返回错误: %v", err)
+		}
+		if user.ID != 1 {
+			t.Errorf("ID = %d, want 1", user.ID)
+		}
+	})
+
+	t.Run("Update 写主库且只更新指定列", func(t *testing.T) {
+		primary, primaryMock := newMockDB(t)
+		replica, _ := newMockDB(t)
+		primaryMock.ExpectBegin()
+		primaryMock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "nick_name"=$1,"updated_at"=$2 WHERE id = $3`)).
+			WithArgs("Alice", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		primaryMock.ExpectCommit()
+
+		if err := NewUserRepositoryWithReplica(primary, replica).Update(ctx, 1, map[string]any{"nick_name": "Alice"}); err != nil {
+			t.Fatalf("Update 返回错误: %v", err)
+		}
+	})
+
+	t.Run("Update 用户不存在返回 404", func(t *testing.T) {
+		primary, primaryMock := newMockDB(t)
+		primaryMock.ExpectBegin()
+		primaryMock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "password"=$1,"updated_at"=$2 WHERE id = $3`)).
+			WithArgs("hash", sqlmock.AnyArg(), 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		primaryMock.ExpectCommit()
+
+		err := NewUserRepository(primary).Update(ctx, 2, map[string]any{"password": "hash"})
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Code != 404 {
+			t.Fatalf("err = %v, want 404", err)
+		}
+	})
+}
+
+func TestCreateBatchSplitsInserts(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	const total = 250
+	users := make([]*models.User, total)
+	for i := range users {
+		users[i] = &models.User{
+			Username: fmt.Sprintf("user%d", i),
+			NickName: fmt.Sprintf("User %d", i),
+			Email:    fmt.Sprintf("user%d@example.com", i),
+			Password: "hash",
+			Role:     models.RoleUser,
+			Status:   models.StatusActive,
+		}
+	}
+
+	// 250 个用户按每批 100 行拆分为 3 条 INSERT，在同一个事务中执行
+	mock.ExpectBegin()
+	nextID := 1
+	for _, size := range []int{100, 100, 50} {
+		rows := sqlmock.NewRows([]string{"id"})
+		for range size {
+			rows.AddRow(nextID)
+			nextID++
+		}
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users"`)).WillReturnRows(rows)
+	}
+	mock.ExpectCommit()
+
+	if err := NewUserRepository(db).CreateBatch(context.Background(), users); err != nil {
+		t.Fatalf("CreateBatch 返回错误: %v", err)
+	}
+	for i, user := range users {
+		if user.ID != uint(i+1) {
+			t.Fatalf("users[%d].ID = %d, want %d", i, user.ID, i+1)
+		}
+	}
+}
+
+// recentTime 匹配距当前不超过一分钟的时间参数
+type recentTime struct{}
+
+func (recentTime) Match(v driver.Value) bool {
+	t, ok := v.(time.Time)
+	return ok && time.Since(t).Abs() < time.Minute
+}
+
+func TestUserTimestamps(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create 设置创建与更新时间", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		user := &models.User{Username: "alice", NickName: "Alice", Email: "alice@example.com", Password: "hash"}
+		if err := NewUserRepository(db).Create(ctx, user); err != nil {
+			t.Fatalf("Create 返回错误: %v", err)
+		}
+		if user.CreatedAt.IsZero() || user.UpdatedAt.IsZero() {
+			t.Errorf("CreatedAt = %v, UpdatedAt = %v, want non-zero", user.CreatedAt, user.UpdatedAt)
+		}
+	})
+
+	t.Run("Update 刷新更新时间", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "nick_name"=$1,"updated_at"=$2 WHERE id = $3`)).
+			WithArgs("Alice L", recentTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		if err := NewUserRepository(db).Update(ctx, 1, map[string]any{"nick_name": "Alice L"}); err != nil {
+			t.Fatalf("Update 返回错误: %v", err)
+		}
+	})
+
+	t.Run("SetStatus 刷新更新时间", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "status"=$1,"updated_at"=$2 WHERE id = $3`)).
+			WithArgs(models.StatusInactive, recentTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		if err := NewUserRepository(db).SetStatus(ctx, 1, models.StatusInactive); err != nil {
+			t.Fatalf("SetStatus 返回错误: %v", err)
+		}
+	})
+}
+
+func TestUserRepositorySearch(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		field    string
+		wantSQL  string
+		wantArg  string
+		dbErr    error
+		wantCode int
+	}{
+		{"按用户名", "ali", "username", `SELECT * FROM "users" WHERE username ILIKE $1 AND "users"."deleted_at" IS NULL ORDER BY id LIMIT $2`, "%ali%", nil, 0},
+		{"按昵称", "Ali", "nick_name", `SELECT * FROM "users" WHERE nick_name ILIKE $1 AND "users"."deleted_at" IS NULL ORDER BY id LIMIT $2`, "%Ali%", nil, 0},
+		{"按邮箱", "example", "email", `SELECT * FROM "users" WHERE email ILIKE $1 AND "users"."deleted_at" IS NULL ORDER BY id LIMIT $2`, "%example%", nil, 0},
+		// 用户输入的通配符按字面量匹配
+		{"转义通配符", `50%_a\b`, "username", `SELECT * FROM "users" WHERE username ILIKE $1 AND "users"."deleted_at" IS NULL ORDER BY id LIMIT $2`, `%50\%\_a\\b%`, nil, 0},
+		// 不在白名单中的字段不会拼接到 SQL
+		{"字段不在白名单", "x", "password", "", "", nil, 400},
+		{"SQL 注入", "x", "username; DROP TABLE users", "", "", nil, 400},
+		{"数据库错误", "ali", "username", `SELECT * FROM "users" WHERE username ILIKE $1 AND "users"."deleted_at" IS NULL ORDER BY id LIMIT $2`, "%ali%", errors.New("connection reset"), 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			if tt.wantSQL != "" {
+				q := mock.ExpectQuery(regexp.QuoteMeta(tt.wantSQL)).WithArgs(tt.wantArg, searchLimit)
+				if tt.dbErr != nil {
+					q.WillReturnError(tt.dbErr)
+				} else {
+					q.WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "alice", "Alice", "alice@example.com", "hash", "user", "active"))
+				}
+			}
+
+			users, err := NewUserRepository(db).Search(context.Background(), tt.query, tt.field)
+			if tt.wantCode != 0 {
+				var appErr *apperror.Error
+				if !errors.As(err, &appErr) || appErr.Code != tt.wantCode {
+					t.Fatalf("err = %v, want %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if len(users) != 1 || users[0].Username != "alice" {
+				t.Errorf("users = %+v, want [alice]", users)
+			}
+		})
+	}
+}
+
+func TestUserRepositorySoftDelete(t *testing.T) {
+	ctx := context.Background()
+	softDelete := regexp.QuoteMeta(`UPDATE "users" SET "deleted_at"=$1 WHERE "users"."id" = $2 AND "users"."deleted_at" IS NULL`)
+
+	t.Run("Delete 只写入删除时间", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(softDelete).WithArgs(recentTime{}, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		if err := NewUserRepository(db).Delete(ctx, 1); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	})
+
+	t.Run("Delete 已删除的用户返回 404", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(softDelete).WithArgs(recentTime{}, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := NewUserRepository(db).Delete(ctx, 1)
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Code != 404 {
+			t.Fatalf("err = %v, want 404", err)
+		}
+	})
+
+	t.Run("DeleteBatch 只删除存在且未删除的用户", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "users" WHERE id IN ($1,$2,$3) AND "users"."deleted_at" IS NULL ORDER BY id FOR UPDATE`)).
+			WithArgs(1, 2, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "deleted_at"=$1 WHERE id IN ($2,$3) AND "users"."deleted_at" IS NULL`)).
+			WithArgs(recentTime{}, 1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		deleted, err := NewUserRepository(db).DeleteBatch(ctx, []uint{1, 2, 3})
+		if err != nil {
+			t.Fatalf("DeleteBatch: %v", err)
+		}
+		if fmt.Sprint(deleted) != "[1 3]" {
+			t.Errorf("deleted = %v, want [1 3]", deleted)
+		}
+	})
+
+	t.Run("DeleteBatch 失败时回滚", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "users"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "deleted_at"`)).
+			WillReturnError(errors.New("connection reset"))
+		mock.ExpectRollback()
+
+		deleted, err := NewUserRepository(db).DeleteBatch(ctx, []uint{1, 2})
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Code != 500 || deleted != nil {
+			t.Fatalf("DeleteBatch = %v, %v, want nil, 500", deleted, err)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gojet/models"
+	"gojet/proto/userpb"
+	"gojet/service"
+	"gojet/util/apperror"
+	"gojet/util/jwt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// contextKey gRPC 上下文中保存用户信息的键类型
+type contextKey struct{}
+
+// NewServer 创建 gRPC 服务器并注册用户服务
+func NewServer(secret string, blacklist jwt.Blacklist) *grpc.Server {
+	s := grpc.NewServer(grpc.UnaryInterceptor(AuthInterceptor(secret, blacklist)))
+	userpb.RegisterUserServiceServer(s, &UserServer{})
+	return s
+}
+
+// AuthInterceptor 校验 gRPC metadata 中的 Bearer token，blacklist 不为空时拒绝已注销的 token
+func AuthInterceptor(secret string, blacklist jwt.Blacklist) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 || values[0] == "" {
+			return nil, status.Error(codes.Unauthenticated, apperror.TokenMissing)
+		}
+
+		t := strings.Replace(values[0], "Bearer ", "", 1)
+		user, err := jwt.ParseToken(t, secret)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Message)
+		}
+		if err := jwt.CheckRevoked(blacklist, user); err != nil {
+			if err.Code == 401 {
+				return nil, status.Error(codes.Unauthenticated, err.Message)
+			}
+			return nil, status.Error(codes.Unavailable, err.Message)
+		}
+
+		return handler(context.WithValue(ctx, contextKey{}, user), req)
+	}
+}
+
+// UserFromContext 获取拦截器写入的用户信息
+func UserFromContext(ctx context.Context) (*jwt.Context, bool) {
+	user, ok := ctx.Value(contextKey{}).(*jwt.Context)
+	return user, ok
+}
+
+// UserServer 用户 gRPC 服务 - 委托给 service 层实现
+type UserServer struct {
+	userpb.UnimplementedUserServiceServer
+}
+
+// GetUser 根据 ID 获取用户
+func (s *UserServer) GetUser(ctx context.Context, req *userpb.GetUserRequest) (*userpb.User, error) {
+	if req.GetId() < 1 {
+		return nil, status.Error(codes.InvalidArgument, apperror.InvalidUserID)
+	}
+
+	user, err := service.GetUserByID(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(user), nil
+}
+
+// ListUsers 分页获取用户
+func (s *UserServer) ListUsers(ctx context.Context, req *userpb.ListUsersRequest) (*userpb.ListUsersResponse, error) {
+	if req.GetPage() < 0 || req.GetSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, apperror.InvalidParams)
+	}
+	page, size := int(req.GetPage()), int(req.GetSize())
+	if page == 0 {
+		page = 1
+	}
+	if size == 0 {
+		size = service.DefaultPageSize
+	}
+	size = min(size, service.MaxPageSize)
+
+	users, total, err := service.GetAllUsers(ctx, page, size)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &userpb.ListUsersResponse{Users: make([]*userpb.User, 0, len(users)), Total: total}
+	for _, user := range users {
+		resp.Users = append(resp.Users, toProto(user))
+	}
+	return resp, nil
+}
+
+// CreateUser 创建用户
+func (s *UserServer) CreateUser(ctx context.Context, req *userpb.CreateUserRequest) (*userpb.User, error) {
+	if req.GetUsername() == "" || req.GetNickName() == "" || req.GetPassword() == "" || req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, apperror.InvalidParams)
+	}
+
+	user, err := service.CreateUser(ctx, &models.User{
+		Username: req.GetUsername(),
+		NickName: req.GetNickName(),
+		Password: req.GetPassword(),
+		Email:    req.GetEmail(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(user), nil
+}
+
+// UpdateUser 更新用户信息
+func (s *UserServer) UpdateUser(ctx context.Context, req *userpb.UpdateUserRequest) (*userpb.User, error) {
+	if req.GetId() < 1 {
+		return nil, status.Error(codes.InvalidArgument, apperror.InvalidUserID)
+	}
+	if req.GetNickName() == "" {
+		return nil, status.Error(codes.InvalidArgument, apperror.InvalidParams)
+	}
+
+	user, err := service.UpdateUser(ctx, uint(req.GetId()), req.GetNickName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(user), nil
+}
+
+// DeleteUser 删除用户，仅管理员可调用
+func (s *UserServer) DeleteUser(ctx context.Context, req *userpb.DeleteUserRequest) (*emptypb.Empty, error) {
+	if user, ok := UserFromContext(ctx); !ok || user.Role != models.RoleAdmin {
+		return nil, status.Error(codes.PermissionDenied, apperror.PermissionDenied)
+	}
+	if req.GetId() < 1 {
+		return nil, status.Error(codes.InvalidArgument, apperror.InvalidUserID)
+	}
+
+	if err := service.DeleteUser(ctx, uint(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// toProto 将用户模型转换为 protobuf 消息（不包含密码）
+func toProto(user *models.User) *userpb.User {
+	return &userpb.User{
+		Id:        int64(user.ID),
+		Username:  user.Username,
+		NickName:  user.NickName,
+		Email:     user.Email,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		CreatedBy: user.CreatedBy,
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+		UpdatedBy: user.UpdatedBy,
+	}
+}
+
+// toStatus 将 AppError 转换为对应的 gRPC 状态码
+func toStatus(err error) error {
+	var e *apperror.Error
+	if !errors.As(err, &e) {
+		return status.Error(codes.Internal, apperror.InternalError)
+	}
+
+	switch e.Code {
+	case 400, 422:
+		return status.Error(codes.InvalidArgument, e.Message)
+	case 401:
+		return status.Error(codes.Unauthenticated, e.Message)
+	case 403:
+		return status.Error(codes.PermissionDenied, e.Message)
+	case 404:
+		return status.Error(codes.NotFound, e.Message)
+	case 409:
+		return status.Error(codes.AlreadyExists, e.Message)
+	default:
+		return status.Error(codes.Internal, e.Message)
+	}
+}
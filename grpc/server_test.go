@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"gojet/models"
+	"gojet/proto/userpb"
+	"gojet/service"
+	"gojet/service/servicetest"
+	"gojet/util/messaging"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateUser(t *testing.T) {
+	if err := models.SetBCryptCost(bcrypt.MinCost); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { models.SetBCryptCost(bcrypt.DefaultCost) })
+
+	tests := []struct {
+		name  string
+		email string
+		want  codes.Code
+	}{
+		{"创建成功", "bob@example.com", codes.OK},
+		// 与 HTTP 接口使用同一个 service 层校验
+		{"邮箱格式错误", "not-an-email", codes.InvalidArgument},
+		{"邮箱已被注册", "alice@example.com", codes.AlreadyExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := servicetest.NewUserRepository(&models.User{ID: 1, Username: "alice", NickName: "Alice", Email: "alice@example.com", Password: "hash"})
+			service.InitService(repo, messaging.NoopPublisher{})
+
+			user, err := (&UserServer{}).CreateUser(context.Background(), &userpb.CreateUserRequest{
+				Username: "bob",
+				NickName: "Bob",
+				Password: "secret123",
+				Email:    tt.email,
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %v, want %v (err %v)", got, tt.want, err)
+			}
+			if tt.want == codes.OK && user.GetEmail() != tt.email {
+				t.Errorf("email = %q, want %q", user.GetEmail(), tt.email)
+			}
+			if tt.want != codes.OK && repo.Calls("Create") != 0 {
+				t.Error("Create called for rejected request")
+			}
+		})
+	}
+}
@@ -1,5 +1,14 @@
 package main
 
+// @title           GoJet API
+// @version         1.0
+// @description     GoJet 用户管理服务 RESTful API
+// @BasePath        /
+
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				格式：Bearer {token}
 func main() {
 	server()
 }
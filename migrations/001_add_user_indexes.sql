@@ -0,0 +1,9 @@
+-- 用户表索引（与 models.User 的 gorm 标签保持一致，索引名使用 GORM 默认命名）
+-- 生产环境可在发布前手动执行，CONCURRENTLY 不会长时间锁表；之后 AutoMigrate 检测到索引已存在会跳过。
+-- 注意：CONCURRENTLY 不能在事务中执行，且创建唯一索引前需先清理重复数据
+--      （可通过 GET /v1/admin/users/duplicates 查找邮箱重复的用户）。
+
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_created_at ON users (created_at);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_status ON users (status);
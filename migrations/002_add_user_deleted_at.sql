@@ -0,0 +1,7 @@
+-- 用户软删除（与 models.User.DeletedAt 保持一致）
+-- 删除用户只写入 deleted_at，查询时自动排除；AutoMigrate 同样会补齐该列与索引。
+-- 生产环境可在发布前手动执行，新增可为空的列不会重写表，CONCURRENTLY 不会长时间锁表。
+-- 注意：CONCURRENTLY 不能在事务中执行。
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
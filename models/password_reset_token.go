@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PasswordResetToken 密码重置令牌，只保存令牌的 SHA-256 哈希
+type PasswordResetToken struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id" gorm:"index"`         // 所属用户ID
+	TokenHash string     `json:"-" gorm:"uniqueIndex;size:64"` // 令牌的 SHA-256 十六进制哈希
+	ExpiresAt time.Time  `json:"expires_at"`                   // 过期时间
+	UsedAt    *time.Time `json:"used_at"`                      // 使用时间，只能使用一次
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (*PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RefreshToken 已签发的 refresh token，只保存 token 的 SHA-256 哈希
+type RefreshToken struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id" gorm:"index"`         // 所属用户ID
+	TokenHash string     `json:"-" gorm:"uniqueIndex;size:64"` // token 的 SHA-256 十六进制哈希
+	ExpiresAt time.Time  `json:"expires_at"`                   // 过期时间
+	RevokedAt *time.Time `json:"revoked_at"`                   // 吊销时间，使用过一次即吊销
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (*RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -1,25 +1,71 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"gojet/util/apperror"
+
+	"github.com/go-playground/validator/v10"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type User struct {
-	ID        int       `json:"id"`                           // 用户ID
-	Username  string    `json:"username" binding:"required"`  // 用户登录名称
-	NickName  string    `json:"nick_name" binding:"required"` // 用户全名
-	Password  string    `json:"password" binding:"required"`  // 用户登录密码
-	Email     string    `json:"email" binding:"required"`     // 用户电子邮箱
-	CreatedAt time.Time `json:"created_at"`
+	ID        uint      `json:"id"`                                                  // 用户ID
+	Username  string    `json:"username" gorm:"uniqueIndex"`                         // 用户登录名称
+	NickName  string    `json:"nick_name"`                                           // 用户昵称（显示名称）
+	Password  string    `json:"-"`                                                   // 用户登录密码的 bcrypt 哈希，不出现在响应与缓存中
+	Email     string    `json:"email" gorm:"uniqueIndex"`                            // 用户电子邮箱
+	Role      string    `json:"role" gorm:"size:20;not null;default:user"`           // 用户角色：user 或 admin
+	Status    string    `json:"status" gorm:"size:20;not null;default:active;index"` // 账号状态：active 或 inactive
+	CreatedAt time.Time `json:"created_at" gorm:"index;autoCreateTime"`
 	CreatedBy string    `json:"created_by"`
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 	UpdatedBy string    `json:"updated_by"`
+
+	// DeletedAt 软删除时间，删除用户时只写入该列，查询自动排除已删除的用户
+	// 唯一索引仍包含已删除的用户，其用户名和邮箱不能被重新注册
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// 用户角色
+const (
+	RoleUser  = "user"  // 普通用户
+	RoleAdmin = "admin" // 管理员，可删除用户、插入初始数据及访问 /v1/admin 接口
+)
+
 func (*User) TableName() string {
-	return "user"
+	return "users"
+}
+
+// 账号状态
+const (
+	StatusActive   = "active"   // 正常
+	StatusInactive = "inactive" // 已停用，不能登录或刷新 token
+)
+
+// UserFilter 用户列表过滤条件，零值字段表示不过滤
+type UserFilter struct {
+	Username      string    `form:"username"`                                         // 用户名（模糊匹配）
+	NickName      string    `form:"nick_name"`                                        // 昵称（模糊匹配）
+	Email         string    `form:"email"`                                            // 邮箱（忽略大小写精确匹配）
+	Status        string    `form:"status" binding:"omitempty,oneof=active inactive"` // 账号状态
+	CreatedAfter  time.Time `form:"created_after"`                                    // 创建时间下限（RFC3339）
+	CreatedBefore time.Time `form:"created_before"`                                   // 创建时间上限（RFC3339）
+}
+
+// DuplicateGroup 邮箱重复的用户分组（忽略大小写）
+type DuplicateGroup struct {
+	Email   string `json:"email"`    // 小写后的邮箱
+	UserIDs []uint `json:"user_ids"` // 使用该邮箱的用户ID
+}
+
+// ETag 根据更新时间计算用户资源的 ETag
+func (u *User) ETag() string {
+	return fmt.Sprintf("\"%x\"", u.UpdatedAt.Unix())
 }
 
 // CompareSimple 使用 bcrypt 验证密码
@@ -29,8 +75,60 @@ func (u *User) CompareSimple(password string) bool {
 	return err == nil
 }
 
+// bcryptCost HashPassword 使用的 bcrypt cost，默认 bcrypt.DefaultCost
+var bcryptCost = bcrypt.DefaultCost
+
+// SetBCryptCost 设置 HashPassword 使用的 bcrypt cost
+// 测试中可设置为 bcrypt.MinCost 加快速度，生产环境可适当调高；需在启动时调用
+func SetBCryptCost(cost int) error {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return fmt.Errorf("bcrypt cost 必须在 %d 到 %d 之间: %d", bcrypt.MinCost, bcrypt.MaxCost, cost)
+	}
+	bcryptCost = cost
+	return nil
+}
+
+// MaxPasswordBytes bcrypt 只使用密码的前 72 字节，超出部分会被静默截断
+const MaxPasswordBytes = 72
+
+// ValidatePassword 校验密码长度是否在 bcrypt 支持的范围内
+func ValidatePassword(password string) error {
+	if len([]byte(password)) > MaxPasswordBytes {
+		return apperror.New(400, apperror.PasswordTooLong)
+	}
+	return nil
+}
+
+// emailValidator 使用与请求绑定相同的 email 规则，HTTP 与 gRPC 入口的校验结果保持一致
+var emailValidator = validator.New()
+
+// ValidateEmail 校验邮箱格式，格式不正确时返回 422
+func ValidateEmail(email string) error {
+	if err := emailValidator.Var(email, "required,email"); err != nil {
+		return apperror.New(422, apperror.InvalidEmail)
+	}
+	return nil
+}
+
+// MinPasswordLength 修改密码时新密码的最小字符数
+const MinPasswordLength = 8
+
+// ValidatePasswordStrength 校验新密码强度：至少 8 个字符且包含数字
+func ValidatePasswordStrength(password string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength || !strings.ContainsAny(password, "0123456789") {
+		return apperror.New(400, apperror.PasswordTooWeak)
+	}
+	return ValidatePassword(password)
+}
+
 // HashPassword 使用 bcrypt 生成密码哈希
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+	if err := ValidatePassword(password); err != nil {
+		return "", err
+	}
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	if err != nil {
+		return "", apperror.Wrap(err, 500, apperror.PasswordHashFailed)
+	}
+	return string(bytes), nil
 }
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"gojet/util/apperror"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm/schema"
+)
+
+func TestUserIndexes(t *testing.T) {
+	s, err := schema.Parse(&User{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("解析 User 结构失败: %v", err)
+	}
+
+	indexes := make(map[string]*schema.Index)
+	for _, idx := range s.ParseIndexes() {
+		indexes[idx.Name] = idx
+	}
+	tests := []struct {
+		name, column string
+		unique       bool
+	}{
+		{"idx_users_username", "username", true},
+		{"idx_users_email", "email", true},
+		{"idx_users_status", "status", false},
+		{"idx_users_created_at", "created_at", false},
+		{"idx_users_deleted_at", "deleted_at", false},
+	}
+	for _, tt := range tests {
+		idx, ok := indexes[tt.name]
+		if !ok {
+			t.Errorf("缺少索引 %s", tt.name)
+			continue
+		}
+		if len(idx.Fields) != 1 || idx.Fields[0].DBName != tt.column {
+			t.Errorf("%s 索引列不正确", tt.name)
+		}
+		if unique := idx.Class == "UNIQUE"; unique != tt.unique {
+			t.Errorf("%s unique = %v, want %v", tt.name, unique, tt.unique)
+		}
+	}
+}
+
+func TestSetBCryptCost(t *testing.T) {
+	t.Cleanup(func() { SetBCryptCost(bcrypt.DefaultCost) })
+
+	for _, cost := range []int{bcrypt.MinCost, bcrypt.MinCost + 1} {
+		if err := SetBCryptCost(cost); err != nil {
+			t.Fatalf("SetBCryptCost(%d): %v", cost, err)
+		}
+		hash, err := HashPassword("secret123")
+		if err != nil {
+			t.Fatalf("HashPassword: %v", err)
+		}
+		if got, err := bcrypt.Cost([]byte(hash)); err != nil || got != cost {
+			t.Errorf("hash cost = %d (err %v), want %d", got, err, cost)
+		}
+	}
+
+	for _, cost := range []int{bcrypt.MinCost - 1, bcrypt.MaxCost + 1} {
+		if err := SetBCryptCost(cost); err == nil {
+			t.Errorf("SetBCryptCost(%d) accepted an out-of-range cost", cost)
+		}
+	}
+	// 设置失败时保留之前的 cost
+	if bcryptCost != bcrypt.MinCost+1 {
+		t.Errorf("bcryptCost = %d after invalid calls, want %d", bcryptCost, bcrypt.MinCost+1)
+	}
+}
+
+func TestPasswordByteLimit(t *testing.T) {
+	t.Cleanup(func() { SetBCryptCost(bcrypt.DefaultCost) })
+	if err := SetBCryptCost(bcrypt.MinCost); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"72 字节", strings.Repeat("a", 72), false},
+		{"73 字节", strings.Repeat("a", 73), true},
+		// 按字节而不是字符计算：24 个汉字为 72 字节，25 个为 75 字节
+		{"72 字节多字节字符", strings.Repeat("密", 24), false},
+		{"超过 72 字节的多字节字符", strings.Repeat("密", 25), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErr := func(fn string, err error) {
+				t.Helper()
+				if !tt.wantErr {
+					if err != nil {
+						t.Fatalf("%s: %v", fn, err)
+					}
+					return
+				}
+				var appErr *apperror.Error
+				if !errors.As(err, &appErr) || appErr.Code != 400 || appErr.Message != apperror.PasswordTooLong {
+					t.Fatalf("%s err = %v, want 400 %s", fn, err, apperror.PasswordTooLong)
+				}
+			}
+
+			checkErr("ValidatePassword", ValidatePassword(tt.password))
+
+			hash, err := HashPassword(tt.password)
+			checkErr("HashPassword", err)
+			if tt.wantErr {
+				// 在哈希之前拒绝，不产生会被 bcrypt 截断的哈希
+				if hash != "" {
+					t.Errorf("HashPassword returned hash %q for rejected password", hash)
+				}
+				return
+			}
+			if !(&User{Password: hash}).CompareSimple(tt.password) {
+				t.Error("hash does not match password")
+			}
+		})
+	}
+}
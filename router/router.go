@@ -2,32 +2,181 @@ package router
 
 import (
 	"gojet/api/v1api"
+	"gojet/config"
+	_ "gojet/docs" // 注册 swag 生成的接口文档
+	"gojet/models"
+	"gojet/util/middleware"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	swaggerfiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-// SetupRoutes 配置所有应用路由
-func SetupRoutes(r *gin.Engine) {
-	apiV1 := r.Group("/v1")
+// routerConfig 路由可选配置
+type routerConfig struct {
+	global      []gin.HandlerFunc // 注册到引擎的全局中间件，按选项顺序执行
+	middlewares []gin.HandlerFunc // 注册到 /v1 路由组的额外中间件
+	debugRoutes bool              // 是否注册调试路由
+	swagger     bool              // 是否注册 Swagger UI
+	metrics     bool              // 是否注册 Prometheus 指标端点
+	gzipMin     int               // 响应体压缩阈值（字节），0 表示不压缩
+	rateLimit   gin.HandlerFunc   // 全局限流中间件，为空时不限流
+	userLimit   gin.HandlerFunc   // /v1/users 按 IP 限流中间件，为空时不限流
+}
+
+// RouterOption 路由配置选项，用于从外部注入可选功能
+type RouterOption func(*routerConfig)
+
+// WithGlobalMiddleware 追加全局中间件，对所有路由（包括未匹配的路由）生效
+// 全局中间件按选项的传入顺序执行，WithTracing、WithMetrics、WithCORS 同样按所在位置插入
+func WithGlobalMiddleware(handlers ...gin.HandlerFunc) RouterOption {
+	return func(rc *routerConfig) {
+		rc.global = append(rc.global, handlers...)
+	}
+}
+
+// WithTracing 为每个请求创建 OpenTelemetry span，应在日志等中间件之前传入以覆盖完整请求
+func WithTracing(serviceName string) RouterOption {
+	return WithGlobalMiddleware(middleware.OTelTracing(serviceName))
+}
+
+// WithCORS 启用跨域中间件，需在 JWT 校验之前传入以直接响应不携带 token 的预检请求
+// 未配置允许的来源时不启用
+func WithCORS(cfg config.CORSConfig) RouterOption {
+	return func(rc *routerConfig) {
+		if cfg.Enabled() {
+			rc.global = append(rc.global, middleware.CORS(cfg))
+		}
+	}
+}
+
+// WithMiddleware 为 /v1 路由组追加中间件
+func WithMiddleware(handlers ...gin.HandlerFunc) RouterOption {
+	return func(rc *routerConfig) {
+		rc.middlewares = append(rc.middlewares, handlers...)
+	}
+}
+
+// WithDebugRoutes 注册 GET /v1/routes 调试路由，仅应在 debug 模式下开启
+func WithDebugRoutes(enabled bool) RouterOption {
+	return func(rc *routerConfig) {
+		rc.debugRoutes = enabled
+	}
+}
+
+// WithSwagger 注册 GET /swagger/*any 接口文档页面
+func WithSwagger(enabled bool) RouterOption {
+	return func(rc *routerConfig) {
+		rc.swagger = enabled
+	}
+}
+
+// WithMetrics 在当前位置插入请求指标中间件，并注册 GET /metrics 输出 Prometheus 默认注册表中的指标
+func WithMetrics(enabled bool) RouterOption {
+	return func(rc *routerConfig) {
+		rc.metrics = enabled
+		if enabled {
+			rc.global = append(rc.global, middleware.Prometheus())
+		}
+	}
+}
+
+// WithGzip 为 /v1 路由组启用 gzip 压缩，响应体达到 minBytes 字节时压缩，minBytes 为 0 时不压缩
+func WithGzip(minBytes int) RouterOption {
+	return func(rc *routerConfig) {
+		rc.gzipMin = minBytes
+	}
+}
+
+// WithRateLimit 为除健康检查外的所有路由组启用全局限流，所有客户端共享配额
+func WithRateLimit(rps, burst int) RouterOption {
+	return func(rc *routerConfig) {
+		rc.rateLimit = middleware.RateLimit(rps, burst)
+	}
+}
+
+// WithUserRateLimit 为 /v1/users 路由组额外启用按 IP 限流，防止单个客户端频繁查询用户列表
+func WithUserRateLimit(rps, burst int) RouterOption {
+	return func(rc *routerConfig) {
+		rc.userLimit = middleware.RateLimitByIP(rps, burst)
+	}
+}
+
+// SetupRoutes 配置所有应用路由，不传选项时只注册基础路由
+func SetupRoutes(r *gin.Engine, opts ...RouterOption) {
+	rc := &routerConfig{}
+	for _, opt := range opts {
+		opt(rc)
+	}
+	// 全局中间件只对之后注册的路由生效，必须在注册路由之前添加
+	r.Use(rc.global...)
+
+	// 压缩需要在其他中间件和处理函数写入响应之前替换 ResponseWriter，因此最先注册
+	var v1Middlewares []gin.HandlerFunc
+	if rc.gzipMin > 0 {
+		v1Middlewares = append(v1Middlewares, middleware.Compress(rc.gzipMin))
+	}
+	apiV1 := r.Group("/v1", append(v1Middlewares, rc.middlewares...)...)
 	{
 		health := apiV1.Group("/health")
 		{
 			health.GET("", v1api.HealthCheck)
+			health.GET("/ready", v1api.ReadinessCheck)
 		}
 
-		users := apiV1.Group("/user")
+		requireAdmin := middleware.RequireRole(models.RoleAdmin)
+
+		// 健康检查用于存活/就绪探针，不参与限流，避免高负载时实例被误判为不可用
+		limited := apiV1.Group("", rc.limiters(rc.rateLimit)...)
+
+		users := limited.Group("/users", rc.limiters(rc.userLimit)...)
 		{
-			users.POST("/insert", v1api.InsertInitialData)
+			users.POST("/insert", requireAdmin, v1api.InsertInitialData)
 			users.POST("", v1api.CreateUser)
+			users.GET("/search", v1api.SearchUsers)
 			users.GET("/:id", v1api.GetUserByID)
 			users.GET("", v1api.GetAllUsers)
 			users.PUT("/:id", v1api.UpdateUser)
-			users.DELETE("/:id", v1api.DeleteUser)
+			users.PUT("/:id/password", v1api.ChangePassword)
+			users.POST("/:id/activate", requireAdmin, v1api.ActivateUser)
+			users.POST("/:id/deactivate", requireAdmin, v1api.DeactivateUser)
+			users.DELETE("/:id", requireAdmin, v1api.DeleteUser)
+			users.DELETE("", requireAdmin, v1api.DeleteUsers)
+		}
+		admin := limited.Group("/admin", requireAdmin)
+		{
+			admin.GET("/users/duplicates", v1api.FindDuplicateUsers)
 		}
-		auth := apiV1.Group("")
+		auth := limited.Group("")
 		{
 			auth.POST("/login", v1api.Login)
 			auth.POST("/register", v1api.Register)
+			auth.POST("/refresh", v1api.Refresh)
+			auth.POST("/logout", v1api.Logout)
+			auth.POST("/password-reset/request", v1api.RequestPasswordReset)
+			auth.POST("/password-reset/confirm", v1api.ConfirmPasswordReset)
 		}
 	}
+
+	if rc.metrics {
+		r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	}
+
+	if rc.swagger {
+		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+	}
+
+	// 调试路由最后注册，确保列出全部路由
+	if rc.debugRoutes {
+		apiV1.GET("/routes", v1api.ListRoutes(r))
+	}
+}
+
+// limiters 将可选的限流中间件转换为 Group 参数，未启用时返回空列表
+func (rc *routerConfig) limiters(handler gin.HandlerFunc) []gin.HandlerFunc {
+	if handler == nil {
+		return nil
+	}
+	return []gin.HandlerFunc{handler}
 }
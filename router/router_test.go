@@ -0,0 +1,201 @@
+package router
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gojet/config"
+	"gojet/models"
+	"gojet/service"
+	"gojet/service/servicetest"
+	"gojet/util/jwt"
+	"gojet/util/messaging"
+	"gojet/util/middleware"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestSetupRoutesWithoutOptions(t *testing.T) {
+	r := gin.New()
+	SetupRoutes(r)
+
+	var paths []string
+	for _, route := range r.Routes() {
+		paths = append(paths, route.Method+" "+route.Path)
+	}
+	for _, want := range []string{"GET /v1/health", "POST /v1/login", "GET /v1/users/:id"} {
+		if !slices.Contains(paths, want) {
+			t.Errorf("route %q not registered", want)
+		}
+	}
+	for _, optional := range []string{"GET /metrics", "GET /swagger/*any", "GET /v1/routes"} {
+		if slices.Contains(paths, optional) {
+			t.Errorf("optional route %q registered without its option", optional)
+		}
+	}
+}
+
+func TestGlobalMiddlewareOrder(t *testing.T) {
+	var order []string
+	mark := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			order = append(order, name)
+			c.Next()
+		}
+	}
+
+	r := gin.New()
+	SetupRoutes(r,
+		WithGlobalMiddleware(mark("first")),
+		WithMetrics(true),
+		WithGlobalMiddleware(mark("second"), mark("third")),
+	)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	if want := []string{"first", "second", "third"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestWithCORSAnswersPreflightBeforeAuth(t *testing.T) {
+	unauthorized := func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) }
+
+	tests := []struct {
+		name string
+		cors config.CORSConfig
+		want int
+	}{
+		{"启用 CORS", config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}, http.StatusNoContent},
+		{"未配置来源", config.CORSConfig{}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			SetupRoutes(r, WithCORS(tt.cors), WithGlobalMiddleware(unauthorized))
+
+			req := httptest.NewRequest(http.MethodOptions, "/v1/users", nil)
+			req.Header.Set("Origin", "https://app.example.com")
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminRoutesRequireAdminRole(t *testing.T) {
+	const secret = "test-secret"
+	sign := func(role string) string {
+		info, err := jwt.Sign(jwt.MapClaims{"id": 1, "username": role, "role": role}, secret, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Token
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		role   string
+		want   int
+	}{
+		{"普通用户删除用户", http.MethodDelete, "/v1/users/2", models.RoleUser, http.StatusForbidden},
+		{"普通用户插入初始数据", http.MethodPost, "/v1/users/insert", models.RoleUser, http.StatusForbidden},
+		{"普通用户查询重复邮箱", http.MethodGet, "/v1/admin/users/duplicates", models.RoleUser, http.StatusForbidden},
+		{"管理员删除用户", http.MethodDelete, "/v1/users/2", models.RoleAdmin, http.StatusNoContent},
+		// 非管理员接口不受角色限制
+		{"普通用户查询用户", http.MethodGet, "/v1/users/2", models.RoleUser, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.InitService(servicetest.NewUserRepository(
+				&models.User{ID: 2, Username: "bob", NickName: "Bob", Email: "bob@example.com", Password: "hash"},
+			), messaging.NoopPublisher{})
+			r := gin.New()
+			SetupRoutes(r, WithGlobalMiddleware(jwt.NewTokenMiddleware([]string{"login"}, secret)))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+sign(tt.role))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	sqlDB, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	// 指标注册在进程级的默认注册表中，-count 多次运行时已注册的错误可以忽略
+	var registered prometheus.AlreadyRegisteredError
+	if err := middleware.RegisterDBStats(sqlDB); err != nil && !errors.As(err, &registered) {
+		t.Fatalf("RegisterDBStats: %v", err)
+	}
+
+	const secret = "test-secret"
+	service.InitService(servicetest.NewUserRepository(
+		&models.User{ID: 1, Username: "alice", NickName: "Alice", Email: "alice@example.com", Password: "hash"},
+	), messaging.NoopPublisher{})
+	r := gin.New()
+	SetupRoutes(r,
+		WithMetrics(true),
+		WithGlobalMiddleware(jwt.NewTokenMiddleware([]string{"metrics"}, secret)),
+	)
+
+	info, err := jwt.Sign(jwt.MapClaims{"id": 1, "username": "alice", "role": models.RoleUser}, secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/1", nil)
+	req.Header.Set("Authorization", "Bearer "+info.Token)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// /metrics 不需要 token
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	body, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		// path 使用路由模板而不是实际路径
+		`http_requests_total{method="GET",path="/v1/users/:id",status="200"}`,
+		`http_request_duration_seconds_count{method="GET",path="/v1/users/:id"}`,
+		"db_open_connections ",
+		"db_in_use ",
+		"db_idle ",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+}
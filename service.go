@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -11,14 +14,24 @@ import (
 	"strings"
 	"time"
 
+	"gojet/api/v1api"
+	"gojet/cache"
 	"gojet/config"
 	"gojet/dao"
+	grpcserver "gojet/grpc"
 	"gojet/models"
 	"gojet/router"
 	"gojet/service"
 	"gojet/util/jwt"
+	"gojet/util/logging"
+	"gojet/util/messaging"
+	"gojet/util/middleware"
+	"gojet/util/tracing"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -40,40 +53,54 @@ func server() {
 type Service struct {
 	Config     *config.Config
 	DB         *gorm.DB
+	Replica    *gorm.DB
 	Logger     *slog.Logger
 	HTTPServer *http.Server
+	GRPCServer *grpc.Server
+	Publisher  messaging.Publisher
+	UserCache  cache.UserCache
+	Blacklist  jwt.Blacklist
+
+	cancel    context.CancelFunc // 停止后台任务
+	stopWatch func()             // 停止配置文件监听
+	logLevel  *slog.LevelVar     // 当前日志级别，配置文件变更时更新
+	logFile   io.Closer          // 日志文件（output 为 file/both 时），Stop 时关闭
+
+	shutdownTracing func(context.Context) error // 导出剩余的 span 并关闭链路追踪
 }
 
-func newService() (*Service, error) {
-	cfg, err := config.LoadConfig("config/config.yaml")
+// configPath 配置文件路径
+const configPath = "config/config.yaml"
+
+// newService 创建服务；返回错误时负责关闭已打开的日志文件，成功时由 Stop 关闭
+func newService() (_ *Service, err error) {
+	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("加载配置失败: %w", err)
 	}
 
-	var logLevel slog.Level
-	switch cfg.Logging.Level {
-	case "debug":
-		logLevel = slog.LevelDebug
-	case "warn":
-		logLevel = slog.LevelWarn
-	case "error":
-		logLevel = slog.LevelError
-	default:
-		logLevel = slog.LevelInfo
-	}
+	// 使用 LevelVar，配置文件变更时无需重建 logger 即可调整日志级别
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(parseLevel(cfg.Logging.Level))
 
-	// 根据配置创建日志处理器（统一使用JSON格式）
+	// 根据配置选择日志输出位置
 	var (
-		handler slog.Handler
 		writer  io.Writer
+		logFile io.Closer
 	)
 	output := strings.ToLower(cfg.Logging.Output)
 	switch output {
 	case "file", "both":
-		fileW, err := fileWriter(cfg.Logging.FilePath)
+		fileW, err := fileWriter(cfg.Logging)
 		if err != nil {
 			return nil, fmt.Errorf("创建日志文件失败: %w", err)
 		}
+		logFile = fileW
+		defer func() {
+			if err != nil {
+				logFile.Close()
+			}
+		}()
 		switch output {
 		case "file":
 			writer = fileW
@@ -85,80 +112,289 @@ func newService() (*Service, error) {
 	default:
 		writer = os.Stdout
 	}
-	handler = slog.NewJSONHandler(writer, &slog.HandlerOptions{
-		Level:     logLevel,
-		AddSource: true,
-	})
 
-	logger := slog.New(handler)
+	// 默认 JSON 格式，format 为 text 时使用文本格式
+	logger := slog.New(newLogHandler(writer, cfg.Logging.Format, logLevel))
 	slog.SetDefault(logger)
 
 	gin.SetMode(cfg.App.Mode)
 
+	// 初始化链路追踪（未启用时为空实现，无需部署 collector）
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, cfg.App.Name, cfg.App.Version)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			shutdownTracing(context.Background())
+		}
+	}()
+
+	if cfg.App.Mode == gin.ReleaseMode && cfg.Database.SSLMode == "disable" {
+		slog.Warn("生产环境数据库连接未启用 SSL，建议将 database.sslmode 设置为 require 或更高级别")
+	}
+
 	// 初始化数据库连接
-	db, err := gorm.Open(postgres.Open(cfg.Database.GetDSN()), &gorm.Config{})
+	db, err := gorm.Open(dialector(cfg.Database.Driver, cfg.Database.GetDSN()), gormConfig())
 	if err != nil {
-		return nil, fmt.Errorf("连接数据库失败: %w", err)
+		return nil, fmt.Errorf("连接数据库失败 (%s): %w", cfg.Database.GetDSNRedacted(), err)
+	}
+	if err := configurePool(db, cfg.Database); err != nil {
+		return nil, err
+	}
+	if err := db.Use(dao.TracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("注册数据库链路追踪插件失败: %w", err)
+	}
+	slog.Info("数据库连接成功", "driver", cfg.Database.Driver, "dsn", cfg.Database.GetDSNRedacted())
+
+	// 初始化只读副本连接（未配置时读写均使用主库）
+	replica := db
+	if cfg.Database.ReplicaDSN != "" {
+		replica, err = gorm.Open(dialector(cfg.Database.Driver, cfg.Database.ReplicaDSN), gormConfig())
+		if err != nil {
+			return nil, fmt.Errorf("连接只读副本失败: %w", err)
+		}
+		if err := configurePool(replica, cfg.Database); err != nil {
+			return nil, err
+		}
+		if err := replica.Use(dao.TracingPlugin{}); err != nil {
+			return nil, fmt.Errorf("注册数据库链路追踪插件失败: %w", err)
+		}
 	}
 
 	// 自动迁移数据库表结构
-	if err := db.AutoMigrate(&models.User{}); err != nil {
+	if err := dao.Migrate(db); err != nil {
 		return nil, fmt.Errorf("数据库迁移失败: %w", err)
 	}
 
-	// 初始化数据访问层和业务层
-	userRepo := dao.NewUserRepository(db)
-	service.InitService(userRepo)
-	service.InitAuth(cfg)
+	// 初始化消息发布者（未配置 NATS 时使用空实现）
+	var publisher messaging.Publisher = messaging.NoopPublisher{}
+	if cfg.NATS.URL != "" {
+		natsPublisher, err := messaging.NewNATSPublisher(cfg.NATS.URL)
+		if err != nil {
+			return nil, err
+		}
+		publisher = natsPublisher
+	}
+
+	// 初始化数据访问层和业务层，用户仓库外层包装按 ID 的读缓存
+	userCache, err := newUserCache(cfg.Cache)
+	if err != nil {
+		return nil, err
+	}
+	userRepo := dao.NewCachedUserRepository(dao.NewUserRepositoryWithReplica(db, replica), userCache)
+	// bcrypt cost 可通过 BCRYPT_COST 环境变量调高（默认 bcrypt.DefaultCost），需在 InitAuth 之前设置
+	if val := os.Getenv("BCRYPT_COST"); val != "" {
+		cost, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("解析 BCRYPT_COST 失败: %w", err)
+		}
+		if err := models.SetBCryptCost(cost); err != nil {
+			return nil, err
+		}
+	}
+
+	service.InitService(userRepo, publisher)
+	blacklist, err := newBlacklist(cfg)
+	if err != nil {
+		return nil, err
+	}
+	service.InitAuth(cfg, dao.NewRefreshTokenRepository(db), dao.NewPasswordResetTokenRepository(db, userCache), blacklist)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库连接池失败: %w", err)
+	}
+	v1api.InitHealth(cfg.App.Version, time.Now(), sqlDB, cfg.Database.GetPoolWaitThreshold())
+	v1api.InitAuth(cfg.JWT.CookieName)
 
-	// 初始化示例数据
-	slog.Info("正在初始化应用示例数据")
-	if err := service.CreateInitialData(); err != nil {
-		return nil, fmt.Errorf("初始化示例数据失败: %w", err)
+	// 初始化示例数据（生产环境可关闭，改为单独执行）
+	if cfg.App.SeedOnStartup {
+		slog.Info("正在初始化应用示例数据")
+		if err := service.CreateInitialData(context.Background()); err != nil {
+			return nil, fmt.Errorf("初始化示例数据失败: %w", err)
+		}
 	}
 
 	// 创建 Gin 路由实例
 	r := gin.New()
 
-	// 配置 JWT 白名单路由（不需要 token 的公开接口）
-	jwt.SkipRouter["login"] = true
-	jwt.SkipRouter["register"] = true
-	jwt.SkipRouter["health"] = true
+	// 配置可信代理，未配置时不信任任何转发头，防止伪造 X-Forwarded-For
+	var trustedProxies []string
+	if len(cfg.App.TrustedProxies) > 0 {
+		trustedProxies = cfg.App.TrustedProxies
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("配置可信代理失败: %w", err)
+	}
 
-	// 添加中间件
-	r.Use(gin.Recovery())
-	r.Use(loggingMiddleware(logger))
+	// 配置 JWT 白名单路由（不需要 token 的公开接口），中间件创建后不可修改
+	skipPaths := []string{"login", "register", "refresh", "health", "ready"}
+	skipPrefixes := []string{"/v1/password-reset/"}
+	debugMode := cfg.App.Mode == gin.DebugMode
+	if debugMode {
+		skipPrefixes = append(skipPrefixes, "/v1/routes")
+	}
+	swaggerEnabled := cfg.IsFeatureEnabled("swagger")
+	if swaggerEnabled {
+		skipPrefixes = append(skipPrefixes, "/swagger/")
+	}
+	metricsEnabled := cfg.IsFeatureEnabled("metrics")
+	if metricsEnabled {
+		skipPaths = append(skipPaths, "metrics")
+	}
+	tokenMiddleware := jwt.NewTokenMiddleware(skipPaths, cfg.JWT.Secret,
+		jwt.WithSkipPrefixes(skipPrefixes...),
+		jwt.WithCookieName(cfg.JWT.CookieName),
+		jwt.WithBlacklist(blacklist),
+	)
 
-	// 设置 JWT secret、数据库连接和配置到 gin 上下文
-	r.Use(func(c *gin.Context) {
-		c.Set("jwt-secret", cfg.JWT.Secret)
-		sqlDB, err := db.DB()
-		if err == nil {
-			c.Set("db", sqlDB)
+	if metricsEnabled {
+		if err := middleware.RegisterDBStats(sqlDB); err != nil {
+			return nil, fmt.Errorf("注册数据库连接池指标失败: %w", err)
 		}
-		c.Set("config", cfg)
+	}
+
+	// 限制请求体大小，防止超大请求体耗尽内存
+	maxBodySize := cfg.App.GetMaxRequestBodySize()
+	bodyLimit := func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize)
 		c.Next()
-	})
-	r.Use(jwt.Token)
+	}
+
+	var timeout []gin.HandlerFunc
+	if d := cfg.App.GetRequestTimeout(); d > 0 {
+		timeout = append(timeout, middleware.Timeout(d))
+	}
 
-	// 设置应用的所有路由
-	router.SetupRoutes(r)
+	// 全局中间件按选项顺序执行：
+	// 1. request ID - 最先执行，保证后续所有日志都带有 request_id；随后是链路追踪和 Prometheus 指标（开启时）
+	// 2. 请求日志 - 在 JWT 之前注册，被 JWT 拒绝的请求同样会被记录
+	// 3. panic 恢复 - 位于日志之后，panic 转换成的 500 响应也会被记录
+	// 4. CORS - 在 JWT 之前直接响应预检请求，预检请求不携带 token
+	// 5. 请求超时 - 位于 panic 恢复之后，处理函数中的 panic 由超时中间件转交给 gin.Recovery
+	// 6. 请求体限制，最后是可能中止请求的 JWT 校验
+	routeOpts := []router.RouterOption{
+		router.WithGlobalMiddleware(middleware.RequestID()),
+		router.WithTracing(cfg.App.Name),
+		router.WithMetrics(metricsEnabled),
+		router.WithGlobalMiddleware(loggingMiddleware(logger, cfg.Logging.GetSkipPaths()), gin.Recovery()),
+		router.WithCORS(cfg.CORS),
+		router.WithGlobalMiddleware(timeout...),
+		router.WithGlobalMiddleware(bodyLimit, tokenMiddleware),
+		router.WithDebugRoutes(debugMode),
+		router.WithSwagger(swaggerEnabled),
+		router.WithGzip(cfg.App.GzipMinBytes),
+	}
+	if rl := cfg.RateLimiting; rl.Enabled {
+		routeOpts = append(routeOpts, router.WithRateLimit(rl.GlobalRPS, rl.BurstSize))
+		if rl.PerIPRPS > 0 {
+			routeOpts = append(routeOpts, router.WithUserRateLimit(rl.PerIPRPS, rl.PerIPBurst))
+		}
+	}
+	router.SetupRoutes(r, routeOpts...)
 
-	// 创建 HTTP 服务器
+	// 创建 HTTP 服务器，设置超时防止慢速连接耗尽资源
+	readTimeout, err := time.ParseDuration(cfg.App.ReadTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("解析读取超时时间失败: %w", err)
+	}
+	writeTimeout, err := time.ParseDuration(cfg.App.WriteTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("解析写入超时时间失败: %w", err)
+	}
+	idleTimeout, err := time.ParseDuration(cfg.App.IdleTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("解析空闲超时时间失败: %w", err)
+	}
 	httpServer := &http.Server{
-		Addr:    ":" + strconv.Itoa(cfg.App.Port),
-		Handler: r,
+		Addr:         ":" + strconv.Itoa(cfg.App.Port),
+		Handler:      r,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		IdleTimeout:  idleTimeout,
+	}
+
+	// 创建 gRPC 服务器（端口为 0 时不启动）
+	var grpcServer *grpc.Server
+	if cfg.App.GRPCPort > 0 {
+		grpcServer = grpcserver.NewServer(cfg.JWT.Secret, blacklist)
 	}
 
+	// 启动连接池监控后台任务，Stop 时取消
+	monitorCtx, cancel := context.WithCancel(context.Background())
+	go monitorPool(monitorCtx, sqlDB, cfg.Database.GetPoolWaitThreshold())
+
 	return &Service{
-		Config:     cfg,
-		DB:         db,
-		Logger:     logger,
-		HTTPServer: httpServer,
+		Config:          cfg,
+		DB:              db,
+		Replica:         replica,
+		Logger:          logger,
+		HTTPServer:      httpServer,
+		GRPCServer:      grpcServer,
+		Publisher:       publisher,
+		UserCache:       userCache,
+		Blacklist:       blacklist,
+		logLevel:        logLevel,
+		shutdownTracing: shutdownTracing,
+		logFile:         logFile,
+		cancel:          cancel,
 	}, nil
 }
 
+// newLogHandler 根据配置创建日志处理器，format 为 text 时使用文本格式，其余取值使用 JSON 格式
+func newLogHandler(w io.Writer, format string, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level:     level,
+		AddSource: true,
+	}
+	if strings.ToLower(format) == "text" {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
+
+// parseLevel 解析日志级别，不支持的取值按 info 处理
+func parseLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
 func (s *Service) Start() error {
+	// 监听配置文件，目前只有日志级别支持热更新，其余配置仍需重启
+	stopWatch, err := config.Watch(configPath, func(cfg *config.Config) {
+		if level := parseLevel(cfg.Logging.Level); level != s.logLevel.Level() {
+			s.logLevel.Set(level)
+			slog.Info("日志级别已更新", "level", level.String())
+		}
+	})
+	if err != nil {
+		// 热更新不影响服务运行，失败时只记录警告
+		slog.Warn("启动配置文件监听失败，配置修改需重启生效", "error", err)
+	} else {
+		s.stopWatch = stopWatch
+	}
+
+	if s.GRPCServer != nil {
+		lis, err := net.Listen("tcp", ":"+strconv.Itoa(s.Config.App.GRPCPort))
+		if err != nil {
+			return fmt.Errorf("监听 gRPC 端口失败: %w", err)
+		}
+		go func() {
+			slog.Info("gRPC 服务器启动中", "端口", s.Config.App.GRPCPort)
+			if err := s.GRPCServer.Serve(lis); err != nil {
+				slog.Error("gRPC 服务器异常退出", "错误", err)
+			}
+		}()
+	}
+
 	slog.Info("服务器启动中", "端口", s.Config.App.Port)
 	return s.HTTPServer.ListenAndServe()
 }
@@ -167,43 +403,233 @@ func (s *Service) Start() error {
 func (s *Service) Stop() error {
 	slog.Info("服务器正在关闭...")
 
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.stopWatch != nil {
+		s.stopWatch()
+	}
+
+	if s.GRPCServer != nil {
+		s.GRPCServer.GracefulStop()
+	}
+
+	if err := s.Publisher.Close(); err != nil {
+		slog.Error("关闭消息发布者失败", "错误", err)
+	}
+
+	if err := s.UserCache.Close(); err != nil {
+		slog.Error("关闭用户缓存失败", "错误", err)
+	}
+
+	if err := s.Blacklist.Close(); err != nil {
+		slog.Error("关闭 token 黑名单失败", "错误", err)
+	}
+
+	// 导出剩余的 span，collector 不可用时最多等待 5 秒
+	tracingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.shutdownTracing(tracingCtx); err != nil {
+		slog.Error("关闭链路追踪失败", "错误", err)
+	}
+
+	if s.Replica != s.DB {
+		if replicaDB, err := s.Replica.DB(); err == nil {
+			if err := replicaDB.Close(); err != nil {
+				slog.Error("关闭只读副本连接失败", "错误", err)
+			}
+		}
+	}
+
 	sqlDB, err := s.DB.DB()
 	if err != nil {
 		return err
 	}
+	if err := sqlDB.Close(); err != nil {
+		return err
+	}
+
+	// 日志文件最后关闭，保证关闭过程中的日志仍能写入
+	if s.logFile != nil {
+		return s.logFile.Close()
+	}
+	return nil
+}
+
+// configurePool 按配置设置数据库连接池参数，主库与只读副本使用相同的配置
+func configurePool(db *gorm.DB, cfg config.DatabaseConfig) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("获取数据库连接池失败: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.GetMaxOpenConns())
+	sqlDB.SetMaxIdleConns(cfg.GetMaxIdleConns())
+	sqlDB.SetConnMaxLifetime(cfg.GetConnMaxLifetime())
+	return nil
+}
+
+// newUserCache 根据配置创建用户缓存实现
+func newUserCache(cfg config.CacheConfig) (cache.UserCache, error) {
+	cacheType := strings.ToLower(cfg.Type)
+	if cacheType == "" || cacheType == "none" {
+		return cache.NoopUserCache{}, nil
+	}
+
+	ttl, err := time.ParseDuration(cfg.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("解析缓存过期时间失败: %w", err)
+	}
+	switch cacheType {
+	case "memory":
+		return cache.NewInMemoryUserCache(cfg.InMemorySize, ttl)
+	case "redis":
+		return cache.NewRedisUserCache(cfg.Address, cfg.Password, cfg.DB, ttl)
+	default:
+		return nil, fmt.Errorf("不支持的缓存类型: %s", cfg.Type)
+	}
+}
 
-	return sqlDB.Close()
+// gormConfig 返回主库与只读副本共用的 GORM 配置，保证错误处理不因连接不同而不同
+// TranslateError 将唯一约束冲突等驱动错误转换为 gorm.ErrDuplicatedKey
+// gorm.Open 会修改传入的配置，每个连接需要单独的实例
+func gormConfig() *gorm.Config {
+	return &gorm.Config{TranslateError: true}
+}
+
+// dialector 根据数据库类型创建 GORM 方言，配置已通过校验，未知类型按 PostgreSQL 处理
+func dialector(driver, dsn string) gorm.Dialector {
+	if driver == config.DriverMySQL {
+		return mysql.Open(dsn)
+	}
+	return postgres.Open(dsn)
+}
+
+// newBlacklist 根据配置创建注销 token 黑名单，redis 类型与用户缓存共用 cache 的连接配置
+func newBlacklist(cfg *config.Config) (jwt.Blacklist, error) {
+	switch strings.ToLower(cfg.JWT.BlacklistType) {
+	case "", "memory":
+		return jwt.NewMemoryBlacklist(), nil
+	case "redis":
+		return jwt.NewRedisBlacklist(cfg.Cache.Address, cfg.Cache.Password, cfg.Cache.DB)
+	default:
+		return nil, fmt.Errorf("不支持的 token 黑名单类型: %s", cfg.JWT.BlacklistType)
+	}
 }
 
-// fileWriter 打开或创建日志文件
-func fileWriter(filePath string) (*os.File, error) {
-	dir := filepath.Dir(filePath)
+// fileWriter 创建按大小自动切割的日志文件写入器，旧文件按数量和天数清理
+func fileWriter(cfg config.LoggingConfig) (io.WriteCloser, error) {
+	// lumberjack 在首次写入时才打开文件，提前创建目录并检查权限，配置错误时启动即失败
+	dir := filepath.Dir(cfg.FilePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("创建日志目录失败: %w", err)
 	}
-	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	f, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("打开日志文件失败: %w", err)
 	}
-	return f, nil
+	f.Close()
+
+	return &lumberjack.Logger{
+		Filename:   cfg.FilePath,
+		MaxSize:    cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
+		Compress:   cfg.Compress,
+		LocalTime:  true,
+	}, nil
+}
+
+// poolMonitor 根据相邻两次采样判断连接池压力
+// sql.DBStats.WaitCount 是进程启动以来的累计值，需与上一次采样比较，否则超过阈值后会一直告警
+type poolMonitor struct {
+	waitThreshold int64
+	lastWaitCount int64
+}
+
+// check 返回本次采样周期内的等待次数，以及等待次数、连接使用率（超过 80%）是否超限
+func (m *poolMonitor) check(stats sql.DBStats) (waits int64, waitExceeded, usageExceeded bool) {
+	waits = stats.WaitCount - m.lastWaitCount
+	m.lastWaitCount = stats.WaitCount
+	waitExceeded = waits > m.waitThreshold
+	usageExceeded = stats.MaxOpenConnections > 0 &&
+		float64(stats.OpenConnections)/float64(stats.MaxOpenConnections) > 0.8
+	return waits, waitExceeded, usageExceeded
+}
+
+// monitorPool 定期检查数据库连接池状态，在连接池压力过大时告警
+// 项目中尚无 webhook 投递服务，连接池即将耗尽时记录 Error 日志，由日志告警规则负责通知
+func monitorPool(ctx context.Context, sqlDB *sql.DB, waitThreshold int64) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("连接池监控异常退出", "panic", r)
+		}
+	}()
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	monitor := &poolMonitor{waitThreshold: waitThreshold, lastWaitCount: sqlDB.Stats().WaitCount}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := sqlDB.Stats()
+			waits, waitExceeded, usageExceeded := monitor.check(stats)
+
+			if waitExceeded {
+				slog.Warn("连接池等待次数过多", "waits", waits, "threshold", waitThreshold)
+			}
+			if usageExceeded {
+				slog.Warn("连接池使用率过高",
+					"open_connections", stats.OpenConnections,
+					"max_open_connections", stats.MaxOpenConnections,
+				)
+			}
+			if waitExceeded && usageExceeded {
+				slog.Error("连接池即将耗尽",
+					"open_connections", stats.OpenConnections,
+					"in_use", stats.InUse,
+					"idle", stats.Idle,
+					"waits", waits,
+					"wait_count", stats.WaitCount,
+					"wait_duration", stats.WaitDuration.String(),
+				)
+			}
+		}
+	}
 }
 
 // loggingMiddleware 请求日志中间件 - 记录 HTTP 请求详情
-func loggingMiddleware(logger *slog.Logger) gin.HandlerFunc {
+// skipPaths 中的路径只有在响应非 200 时才记录，避免健康检查刷屏
+func loggingMiddleware(logger *slog.Logger, skipPaths []string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, p := range skipPaths {
+		skip[strings.TrimSpace(p)] = true
+	}
+
 	return func(c *gin.Context) {
 		start := time.Now()
 
 		c.Next()
 
+		// 在 c.Next() 之后判断，保证异常的健康检查响应仍会被记录
+		if skip[c.Request.URL.Path] && c.Writer.Status() == http.StatusOK {
+			return
+		}
+
 		// 记录请求详情
 		duration := time.Since(start)
 		logger.Info("HTTP Request",
+			logging.RequestIDKey, c.GetString(logging.RequestIDKey),
 			"method", c.Request.Method,
 			"path", c.Request.URL.Path,
 			"status", c.Writer.Status(),
 			"duration", duration.String(),
 			"user_agent", c.Request.UserAgent(),
-			"ip", c.ClientIP(),
+			// client_ip 依赖可信代理配置，同时记录直连地址 remote_addr 便于排查代理配置问题
+			"client_ip", c.ClientIP(),
+			"remote_addr", c.Request.RemoteAddr,
 		)
 	}
 }
@@ -1,70 +1,295 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"gojet/config"
+	"gojet/models"
 	"gojet/util/apperror"
 	"gojet/util/jwt"
+	"gojet/util/logging"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 var cfg *config.Config
 
-// InitAuth 初始化认证服务
-func InitAuth(config *config.Config) {
+// refreshTokenRepo 包级变量，存储 refresh token 仓库实例
+var refreshTokenRepo RefreshTokenRepository
+
+// resetTokenRepo 包级变量，存储密码重置令牌仓库实例
+var resetTokenRepo PasswordResetTokenRepository
+
+// blacklist 包级变量，存储已注销 accessToken 的黑名单
+var blacklist jwt.Blacklist
+
+// passwordResetTTL 密码重置令牌有效期
+const passwordResetTTL = 30 * time.Minute
+
+// dummyHash 用户不存在时用于比较的 bcrypt 哈希，避免通过响应时间判断用户是否存在
+// InitAuth 会按当前 bcrypt cost 重新计算，保证与真实用户密码的比较耗时一致
+var dummyHash = "$2a$10$5sntFPsKctUA7FMdv1eamO0f01NxYB00kXsqBsEHFjqUe/Gpbddtq"
+
+// InitAuth 初始化认证服务，需在 models.SetBCryptCost 之后调用
+func InitAuth(config *config.Config, tokens RefreshTokenRepository, resetTokens PasswordResetTokenRepository, revoked jwt.Blacklist) {
 	cfg = config
+	refreshTokenRepo = tokens
+	resetTokenRepo = resetTokens
+	blacklist = revoked
+	if hash, err := models.HashPassword(uuid.NewString()); err == nil {
+		dummyHash = hash
+	}
 }
 
 // LoginReq 登录请求参数
 type LoginReq struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Identity string `json:"username" binding:"required"` // 用户名或邮箱（字段名保持 username 以兼容旧客户端）
+	Password string `json:"password" binding:"required"` // 登录密码
 }
 
 // LoginResp 登录响应数据
 type LoginResp struct {
-	Userid      int     `json:"userid"`       // 用户ID
-	Username    string  `json:"username"`     // 用户名称
-	NickName    string  `json:"nick_name"`    // 用户别名
-	AccessToken string  `json:"access_token"` // accessToken
-	ExpiresIn   float64 `json:"expires_in"`   // 过期时间
-	TokenType   string  `json:"token_type"`   // token类型
+	Userid           uint    `json:"userid"`             // 用户ID
+	Username         string  `json:"username"`           // 用户名称
+	NickName         string  `json:"nick_name"`          // 用户昵称（显示名称）
+	AccessToken      string  `json:"access_token"`       // accessToken
+	ExpiresIn        float64 `json:"expires_in"`         // 过期时间
+	TokenType        string  `json:"token_type"`         // token类型
+	RefreshToken     string  `json:"refresh_token"`      // 用于换取新 token 的 refreshToken，仅能使用一次
+	RefreshExpiresIn float64 `json:"refresh_expires_in"` // refreshToken 过期时间
 }
 
 // Login 执行登录逻辑
 func (req *LoginReq) Login(ctx *gin.Context) (*LoginResp, error) {
-	user, err := userRepo.GetUserByUserName(req.Username)
+	user, err := userRepo.GetByEmailOrUsername(ctx.Request.Context(), req.Identity)
 	if err != nil {
-		return nil, apperror.Wrap(err, 404, apperror.UserNotFound)
+		var appErr *apperror.Error
+		if errors.As(err, &appErr) && appErr.Code == 404 {
+			// 用户不存在与密码错误返回相同的结果和相近的耗时
+			(&models.User{Password: dummyHash}).CompareSimple(req.Password)
+			return nil, apperror.New(401, apperror.AuthFailed)
+		}
+		return nil, err
 	}
 
 	// 验证密码
 	if !user.CompareSimple(req.Password) {
 		return nil, apperror.New(401, apperror.AuthFailed)
 	}
+	// 密码校验通过后再检查状态，避免未持有密码的人探测账号是否被停用
+	if user.Status == models.StatusInactive {
+		return nil, apperror.New(403, apperror.AccountDisabled)
+	}
+
+	return issueTokens(ctx.Request.Context(), user)
+}
+
+// RefreshReq 刷新 token 请求参数
+type RefreshReq struct {
+	AccessToken  string `json:"access_token" binding:"required"`  // 登录时获得的 accessToken，允许已过期
+	RefreshToken string `json:"refresh_token" binding:"required"` // 登录或上次刷新时获得的 refreshToken
+}
+
+// Refresh 校验 refreshToken 并签发新的 accessToken/refreshToken，旧的 refreshToken 随即失效
+func (req *RefreshReq) Refresh(ctx *gin.Context) (*LoginResp, error) {
+	// accessToken 只校验签名，过期后仍可从中取出用户
+	access, appErr := jwt.ParseExpiredToken(req.AccessToken, cfg.JWT.Secret)
+	if appErr != nil {
+		return nil, apperror.Wrap(appErr, 401, appErr.Message)
+	}
+	userID, appErr := jwt.ParseRefreshToken(req.RefreshToken, cfg.JWT.Secret)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if userID != access.ID {
+		return nil, apperror.New(401, apperror.RefreshTokenInvalid)
+	}
+	// 已注销的 accessToken 不能再换取新 token
+	if appErr := jwt.CheckRevoked(blacklist, access); appErr != nil {
+		return nil, appErr
+	}
+
+	// 校验 refreshToken 未被使用并立即吊销（轮换），同一个 refreshToken 只能成功刷新一次
+	revoked, err := refreshTokenRepo.Revoke(ctx.Request.Context(), hashToken(req.RefreshToken), userID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, apperror.New(401, apperror.RefreshTokenInvalid)
+	}
+
+	user, err := userRepo.GetByID(ctx.Request.Context(), userID)
+	if err != nil {
+		// 用户已被删除时不再签发 token
+		var notFound *apperror.Error
+		if errors.As(err, &notFound) && notFound.Code == 404 {
+			return nil, apperror.New(401, apperror.AuthFailed)
+		}
+		return nil, err
+	}
+	if user.Status == models.StatusInactive {
+		return nil, apperror.New(403, apperror.AccountDisabled)
+	}
+
+	return issueTokens(ctx.Request.Context(), user)
+}
+
+// Logout 吊销当前 accessToken，黑名单记录保留到 token 原本的过期时间
+func Logout(ctx context.Context, jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return apperror.New(401, apperror.TokenInvalid)
+	}
+	if err := blacklist.Revoke(jti, time.Until(expiresAt)); err != nil {
+		return apperror.Wrap(err, 503, apperror.TokenCheckFailed)
+	}
+	logging.LoggerFromContext(ctx).Info("用户已注销", "jti", jti)
+	return nil
+}
+
+// PasswordResetRequestReq 申请密码重置请求参数
+type PasswordResetRequestReq struct {
+	Email string `json:"email" binding:"required,email"` // 注册邮箱
+}
 
+// Request 为邮箱对应的用户生成密码重置令牌
+// 邮箱未注册时同样返回成功，避免通过该接口判断邮箱是否已注册
+func (req *PasswordResetRequestReq) Request(ctx *gin.Context) error {
+	user, err := userRepo.GetByEmail(ctx.Request.Context(), req.Email)
+	if err != nil {
+		var appErr *apperror.Error
+		if errors.As(err, &appErr) && appErr.Code == 404 {
+			return nil
+		}
+		return err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return apperror.Wrap(err, 500, "生成密码重置令牌失败")
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := resetTokenRepo.Create(ctx.Request.Context(), &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}); err != nil {
+		return err
+	}
+
+	// 暂未接入邮件服务，令牌只记录在日志中
+	logging.LoggerFromContext(ctx.Request.Context()).Info("已生成密码重置令牌",
+		"user_id", user.ID, "token", token, "expires_in", passwordResetTTL.String())
+	return nil
+}
+
+// PasswordResetConfirmReq 确认密码重置请求参数
+type PasswordResetConfirmReq struct {
+	Token       string `json:"token" binding:"required"`        // 密码重置令牌
+	NewPassword string `json:"new_password" binding:"required"` // 新密码（最长 72 字节）
+}
+
+// Confirm 校验密码重置令牌并设置新密码，令牌随即失效，该用户已签发的 refreshToken 全部吊销
+func (req *PasswordResetConfirmReq) Confirm(ctx *gin.Context) error {
+	hashedPassword, err := models.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := resetTokenRepo.ResetPassword(ctx.Request.Context(), hashToken(req.Token), hashedPassword)
+	if err != nil {
+		return err
+	}
+	if userID == 0 {
+		return apperror.New(400, apperror.PasswordResetTokenInvalid)
+	}
+
+	logging.LoggerFromContext(ctx.Request.Context()).Info("密码重置成功", "user_id", userID)
+	return nil
+}
+
+// ChangePassword 校验原密码后修改密码，并吊销该用户已签发的全部 refreshToken
+// 当前 accessToken 在过期前仍然有效，其他会话无法再刷新 token
+func ChangePassword(ctx context.Context, id uint, oldPassword, newPassword string) error {
+	if err := models.ValidatePasswordStrength(newPassword); err != nil {
+		return err
+	}
+
+	// 从主库读取，避免复制延迟导致使用修改前的旧密码也能通过校验
+	user, err := userRepo.GetByIDFromPrimary(ctx, id)
+	if err != nil {
+		return apperror.PassThrough(err, 500, apperror.DBQueryError)
+	}
+	if !user.CompareSimple(oldPassword) {
+		return apperror.New(401, apperror.OldPasswordWrong)
+	}
+
+	hashedPassword, err := models.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := userRepo.Update(ctx, id, map[string]any{"password": hashedPassword}); err != nil {
+		return apperror.PassThrough(err, 500, apperror.UserUpdateFailed)
+	}
+
+	if err := refreshTokenRepo.RevokeAll(ctx, id); err != nil {
+		return err
+	}
+	logging.LoggerFromContext(ctx).Info("修改密码成功", "user_id", id)
+	return nil
+}
+
+// issueTokens 为用户签发 accessToken 与 refreshToken，并保存 refreshToken 的哈希
+func issueTokens(ctx context.Context, user *models.User) (*LoginResp, error) {
 	// 设置token过期时间
 	var duration = time.Duration(cfg.JWT.ExpireHours) * time.Hour
 
 	// 生成JWT token
-	secret, exists := ctx.Get("jwt-secret")
-	if !exists {
-		return nil, apperror.New(500, "JWT secret 未配置")
+	// 项目没有租户模型，不签发 tenant_id；引入多租户时在这里补充
+	claims := jwt.MapClaims{
+		"id":       user.ID,
+		"username": user.Username,
+		"role":     user.Role,
+		"jti":      uuid.NewString(),
+	}
+	tokenInfo, err := jwt.Sign(claims, cfg.JWT.Secret, duration)
+	if err != nil {
+		return nil, apperror.Wrap(err, 500, "生成Token失败")
 	}
 
-	token, err := jwt.Sign(jwt.Context{ID: user.ID, Username: user.Username}, secret.(string), duration)
+	refreshDuration := time.Duration(cfg.JWT.RefreshExpireHours) * time.Hour
+	refreshInfo, err := jwt.SignRefresh(user.ID, cfg.JWT.Secret, refreshDuration)
 	if err != nil {
 		return nil, apperror.Wrap(err, 500, "生成Token失败")
 	}
+	if err := refreshTokenRepo.Create(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshInfo.Token),
+		ExpiresAt: refreshInfo.ExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
 
 	resp := &LoginResp{
-		Userid:      user.ID,
-		Username:    user.Username,
-		NickName:    user.NickName,
-		AccessToken: token,
-		TokenType:   "Bearer",
-		ExpiresIn:   duration.Seconds(),
+		Userid:           user.ID,
+		Username:         user.Username,
+		NickName:         user.NickName,
+		AccessToken:      tokenInfo.Token,
+		TokenType:        "Bearer",
+		ExpiresIn:        time.Until(tokenInfo.ExpiresAt).Round(time.Second).Seconds(),
+		RefreshToken:     refreshInfo.Token,
+		RefreshExpiresIn: time.Until(refreshInfo.ExpiresAt).Round(time.Second).Seconds(),
 	}
 	return resp, nil
 }
+
+// hashToken 计算 token 的 SHA-256 哈希，数据库中只保存哈希，泄露后无法直接使用
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
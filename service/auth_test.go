@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"gojet/config"
+	"gojet/models"
+	"gojet/service/servicetest"
+	"gojet/util/apperror"
+
+	"github.com/gin-gonic/gin"
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// setupLoginTiming 使用默认 bcrypt cost 初始化认证服务，返回已存在的用户名
+// 默认 cost 下两条路径的耗时都由 bcrypt 决定，才能体现时间差
+func setupLoginTiming(tb testing.TB) string {
+	tb.Helper()
+	if err := models.SetBCryptCost(bcrypt.DefaultCost); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { models.SetBCryptCost(bcrypt.MinCost) })
+
+	hash, err := models.HashPassword("secret123")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	InitService(servicetest.NewUserRepository(&models.User{Username: "alice", Email: "alice@example.com", Password: hash}), nil)
+	InitAuth(config.DefaultConfig(), servicetest.NewRefreshTokenRepository(), nil, nil)
+	tb.Cleanup(func() { userRepo = nil })
+	return "alice"
+}
+
+// failLogin 执行一次失败的登录，返回耗时
+func failLogin(tb testing.TB, identity string) time.Duration {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/v1/login", nil)
+	req := &LoginReq{Identity: identity, Password: "wrong-password"}
+
+	start := time.Now()
+	if _, err := req.Login(c); err == nil {
+		tb.Fatal("Login succeeded with a wrong password")
+	}
+	return time.Since(start)
+}
+
+func BenchmarkLoginUnknownUser(b *testing.B) {
+	setupLoginTiming(b)
+	for b.Loop() {
+		failLogin(b, "nobody")
+	}
+}
+
+func BenchmarkLoginWrongPassword(b *testing.B) {
+	username := setupLoginTiming(b)
+	for b.Loop() {
+		failLogin(b, username)
+	}
+}
+
+func TestLoginTimingEqualized(t *testing.T) {
+	if testing.Short() {
+		t.Skip("耗时测试，-short 时跳过")
+	}
+	username := setupLoginTiming(t)
+
+	const runs = 100
+	var unknown, wrong time.Duration
+	for range runs {
+		unknown += failLogin(t, "nobody")
+		wrong += failLogin(t, username)
+	}
+	unknown /= runs
+	wrong /= runs
+
+	if diff := (unknown - wrong).Abs(); diff >= 10*time.Millisecond {
+		t.Errorf("平均耗时相差 %v（用户不存在 %v，密码错误 %v），应小于 10ms", diff, unknown, wrong)
+	}
+}
+
+func TestChangePasswordStrength(t *testing.T) {
+	hash, err := models.HashPassword("secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name, password string
+		wantWeak       bool
+	}{
+		{"少于 8 位", "abc123", true},
+		{"不包含数字", "password", true},
+		{"多字节字符但不包含数字", "长度足够但没有数字", true},
+		{"符合要求", "new-secret123", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := setup(t, &models.User{ID: 1, Username: "alice", Email: "alice@example.com", Password: hash})
+			err := ChangePassword(context.Background(), 1, "secret123", tt.password)
+			var appErr *apperror.Error
+			weak := errors.As(err, &appErr) && appErr.Code == 400 && appErr.Message == apperror.PasswordTooWeak
+			if weak != tt.wantWeak || (!tt.wantWeak && err != nil) {
+				t.Fatalf("ChangePassword(%q) err = %v, want weak %v", tt.password, err, tt.wantWeak)
+			}
+			if n := repo.Calls("Update"); (n == 0) != tt.wantWeak {
+				t.Errorf("Update called %d times", n)
+			}
+		})
+	}
+}
+
+func TestCreateUserKeepsExistingPasswordRules(t *testing.T) {
+	repo := setup(t)
+	// 强度要求只用于修改密码，注册与创建用户沿用原有规则，已有客户端不受影响
+	if _, err := CreateUser(context.Background(), &models.User{Username: "alice", NickName: "Alice", Email: "alice@example.com", Password: "123456"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if n := repo.Calls("Create"); n != 1 {
+		t.Errorf("Create called %d times, want 1", n)
+	}
+}
+
+func TestIssueTokensClaims(t *testing.T) {
+	setup(t)
+	conf := config.DefaultConfig()
+	conf.JWT.Secret = "test-secret"
+	InitAuth(conf, servicetest.NewRefreshTokenRepository(), nil, nil)
+	t.Cleanup(func() { cfg, refreshTokenRepo = nil, nil })
+
+	resp, err := issueTokens(context.Background(), &models.User{ID: 7, Username: "alice", Role: models.RoleAdmin})
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+	claims := jwtlib.MapClaims{}
+	if _, err := jwtlib.ParseWithClaims(resp.AccessToken, claims, func(*jwtlib.Token) (any, error) {
+		return []byte("test-secret"), nil
+	}); err != nil {
+		t.Fatalf("parse access token: %v", err)
+	}
+
+	// 没有租户模型，不包含 tenant_id
+	want := []string{"exp", "iat", "id", "jti", "nbf", "role", "username"}
+	if got := slices.Sorted(maps.Keys(claims)); !slices.Equal(got, want) {
+		t.Errorf("claims = %v, want %v", got, want)
+	}
+	if claims["id"] != float64(7) || claims["username"] != "alice" || claims["role"] != models.RoleAdmin {
+		t.Errorf("claims = %v", claims)
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"gojet/models"
+)
+
+// UserRepository 用户数据访问接口 - 由 dao.UserRepository 实现
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	CreateBatch(ctx context.Context, users []*models.User) error
+	GetAll(ctx context.Context, page, size int) ([]*models.User, int64, error)
+	GetFiltered(ctx context.Context, filter models.UserFilter, page, size int) ([]*models.User, int64, error)
+	Search(ctx context.Context, query string, field string) ([]*models.User, error)
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetByIDFromPrimary(ctx context.Context, id uint) (*models.User, error)
+	GetUserByUserName(ctx context.Context, username string) (*models.User, error)
+	GetByEmailOrUsername(ctx context.Context, value string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	FindEmailDuplicates(ctx context.Context) ([]models.DuplicateGroup, error)
+	Update(ctx context.Context, id uint, fields map[string]any) error
+	SetStatus(ctx context.Context, id uint, status string) error
+	Delete(ctx context.Context, id uint) error
+	DeleteBatch(ctx context.Context, ids []uint) ([]uint, error)
+	WithAdvisoryLock(ctx context.Context, lockID int64, fn func() error) (bool, error)
+}
+
+// RefreshTokenRepository refresh token 数据访问接口 - 由 dao.RefreshTokenRepository 实现
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	Revoke(ctx context.Context, tokenHash string, userID uint) (bool, error)
+	RevokeAll(ctx context.Context, userID uint) error
+}
+
+// PasswordResetTokenRepository 密码重置令牌数据访问接口 - 由 dao.PasswordResetTokenRepository 实现
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uint, error)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+
+	"gojet/models"
+	"gojet/service/servicetest"
+	"gojet/util/messaging"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 编译期检查内存仓库是否实现了 service 层依赖的接口
+var _ UserRepository = (*servicetest.UserRepository)(nil)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	// 测试中使用最小 cost，避免 bcrypt 拖慢测试
+	if err := models.SetBCryptCost(bcrypt.MinCost); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// setup 使用内存仓库初始化 service 层，测试结束后恢复包级变量
+func setup(t *testing.T, users ...*models.User) *servicetest.UserRepository {
+	t.Helper()
+	repo := servicetest.NewUserRepository(users...)
+	InitService(repo, messaging.NoopPublisher{})
+	t.Cleanup(func() {
+		userRepo = nil
+		publisher = messaging.NoopPublisher{}
+	})
+	return repo
+}
+
+// message 已发布的消息
+type message struct {
+	subject string
+	data    []byte
+}
+
+// recordingPublisher 记录发布的消息，序列化方式与 NATSPublisher 一致
+type recordingPublisher struct {
+	mu       sync.Mutex
+	messages []message
+}
+
+func (p *recordingPublisher) Publish(subject string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, message{subject: subject, data: data})
+	return nil
+}
+
+func (p *recordingPublisher) Close() error { return nil }
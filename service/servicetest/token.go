@@ -0,0 +1,122 @@
+package servicetest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gojet/models"
+)
+
+// RefreshTokenRepository 内存版 refresh token 仓库，行为与 dao.RefreshTokenRepository 保持一致
+type RefreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]*models.RefreshToken // 键为 token 哈希
+}
+
+// NewRefreshTokenRepository 创建内存 refresh token 仓库
+func NewRefreshTokenRepository() *RefreshTokenRepository {
+	return &RefreshTokenRepository{tokens: make(map[string]*models.RefreshToken)}
+}
+
+// Create 保存 refresh token
+func (r *RefreshTokenRepository) Create(_ context.Context, token *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := *token
+	t.CreatedAt = time.Now()
+	r.tokens[token.TokenHash] = &t
+	return nil
+}
+
+// Revoke 吊销属于 userID 且未过期、未吊销的 refresh token
+func (r *RefreshTokenRepository) Revoke(_ context.Context, tokenHash string, userID uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[tokenHash]
+	now := time.Now()
+	if !ok || t.UserID != userID || t.RevokedAt != nil || !t.ExpiresAt.After(now) {
+		return false, nil
+	}
+	t.RevokedAt = &now
+	return true, nil
+}
+
+// RevokeAll 吊销用户所有未吊销的 refresh token
+func (r *RefreshTokenRepository) RevokeAll(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revokeAll(userID)
+	return nil
+}
+
+// Active 返回用户未吊销的 refresh token 数量
+func (r *RefreshTokenRepository) Active(userID uint) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// revokeAll 吊销用户所有未吊销的 refresh token，调用方需持有 mu
+func (r *RefreshTokenRepository) revokeAll(userID uint) {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+}
+
+// PasswordResetTokenRepository 内存版密码重置令牌仓库，重置密码时同时修改用户仓库与 refresh token 仓库
+type PasswordResetTokenRepository struct {
+	mu            sync.Mutex
+	tokens        map[string]*models.PasswordResetToken // 键为令牌哈希
+	users         *UserRepository
+	refreshTokens *RefreshTokenRepository
+}
+
+// NewPasswordResetTokenRepository 创建内存密码重置令牌仓库
+func NewPasswordResetTokenRepository(users *UserRepository, refreshTokens *RefreshTokenRepository) *PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{
+		tokens:        make(map[string]*models.PasswordResetToken),
+		users:         users,
+		refreshTokens: refreshTokens,
+	}
+}
+
+// Create 保存密码重置令牌
+func (r *PasswordResetTokenRepository) Create(_ context.Context, token *models.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := *token
+	t.CreatedAt = time.Now()
+	r.tokens[token.TokenHash] = &t
+	return nil
+}
+
+// ResetPassword 使用未过期、未使用的令牌重置密码并吊销该用户的全部 refresh token，令牌无效时返回 0
+func (r *PasswordResetTokenRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[tokenHash]
+	now := time.Now()
+	if !ok || t.UsedAt != nil || !t.ExpiresAt.After(now) {
+		return 0, nil
+	}
+	if err := r.users.Update(ctx, t.UserID, map[string]any{"password": passwordHash}); err != nil {
+		// 用户已被删除
+		return 0, nil
+	}
+	t.UsedAt = &now
+
+	r.refreshTokens.mu.Lock()
+	defer r.refreshTokens.mu.Unlock()
+	r.refreshTokens.revokeAll(t.UserID)
+	return t.UserID, nil
+}
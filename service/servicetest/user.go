@@ -0,0 +1,411 @@
+// Package servicetest 提供 service 层数据访问接口的内存实现，用于 service、api、grpc 等包的测试
+package servicetest
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gojet/models"
+	"gojet/util/apperror"
+
+	"gorm.io/gorm"
+)
+
+// UserRepository 内存版用户仓库，行为与 dao.UserRepository 保持一致：
+// 记录不存在返回 404，用户名或邮箱重复返回 409，删除为软删除，返回的用户均为副本
+type UserRepository struct {
+	mu      sync.Mutex
+	users   map[uint]*models.User
+	deleted map[uint]*models.User // 已软删除的用户，查询时不可见，但仍占用用户名和邮箱
+	nextID  uint
+	calls   map[string]int
+	errs    map[string]error
+	lock    sync.Mutex
+
+	// BeforeGetByID 不为空时在 GetByID 查询前调用，用于模拟慢查询
+	BeforeGetByID func()
+}
+
+// NewUserRepository 创建内存用户仓库，users 作为初始数据（ID 为 0 时自动分配）
+func NewUserRepository(users ...*models.User) *UserRepository {
+	r := &UserRepository{
+		users:   make(map[uint]*models.User),
+		deleted: make(map[uint]*models.User),
+		calls:   make(map[string]int),
+		errs:    make(map[string]error),
+	}
+	for _, user := range users {
+		r.insert(user)
+	}
+	return r
+}
+
+// FailOn 使指定方法返回 err，err 为 nil 时恢复正常
+func (r *UserRepository) FailOn(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs[method] = err
+}
+
+// Calls 返回指定方法被调用的次数
+func (r *UserRepository) Calls(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[method]
+}
+
+// Users 按 ID 顺序返回当前保存的全部用户
+func (r *UserRepository) Users() []*models.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sorted()
+}
+
+// begin 记录调用并返回注入的错误，调用方需持有 mu
+func (r *UserRepository) begin(method string) error {
+	r.calls[method]++
+	return r.errs[method]
+}
+
+// insert 保存用户副本并回填 ID 与时间戳，调用方需持有 mu 或处于初始化阶段
+func (r *UserRepository) insert(user *models.User) {
+	if user.ID == 0 {
+		r.nextID++
+		user.ID = r.nextID
+	}
+	r.nextID = max(r.nextID, user.ID)
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+	u := *user
+	r.users[user.ID] = &u
+}
+
+// duplicated 判断用户名或邮箱（忽略大小写）是否已被使用，与唯一索引一致，包括已删除的用户
+func (r *UserRepository) duplicated(user *models.User) bool {
+	for _, u := range r.users {
+		if u.Username == user.Username || strings.EqualFold(u.Email, user.Email) {
+			return true
+		}
+	}
+	for _, u := range r.deleted {
+		if u.Username == user.Username || strings.EqualFold(u.Email, user.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// softDelete 将用户移入已删除集合并记录删除时间，调用方需持有 mu
+func (r *UserRepository) softDelete(id uint) {
+	u := r.users[id]
+	u.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.deleted[id] = u
+	delete(r.users, id)
+}
+
+// sorted 按 ID 顺序返回用户副本
+func (r *UserRepository) sorted() []*models.User {
+	users := make([]*models.User, 0, len(r.users))
+	for _, u := range r.users {
+		c := *u
+		users = append(users, &c)
+	}
+	slices.SortFunc(users, func(a, b *models.User) int { return cmp.Compare(a.ID, b.ID) })
+	return users
+}
+
+// find 返回第一个满足条件的用户副本
+func (r *UserRepository) find(match func(*models.User) bool) (*models.User, error) {
+	for _, u := range r.sorted() {
+		if match(u) {
+			return u, nil
+		}
+	}
+	return nil, apperror.New(404, apperror.RecordNotFound)
+}
+
+// Create 保存用户，用户名或邮箱已存在时返回 409
+func (r *UserRepository) Create(_ context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("Create"); err != nil {
+		return err
+	}
+	if r.duplicated(user) {
+		return apperror.New(409, apperror.UserDuplicate)
+	}
+	r.insert(user)
+	return nil
+}
+
+// CreateBatch 批量保存用户，任意一个重复时全部不保存
+func (r *UserRepository) CreateBatch(_ context.Context, users []*models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("CreateBatch"); err != nil {
+		return err
+	}
+	for _, user := range users {
+		if r.duplicated(user) {
+			return apperror.New(500, apperror.DBInsertError)
+		}
+	}
+	for _, user := range users {
+		r.insert(user)
+	}
+	return nil
+}
+
+// GetAll 分页获取所有用户
+func (r *UserRepository) GetAll(ctx context.Context, page, size int) ([]*models.User, int64, error) {
+	return r.GetFiltered(ctx, models.UserFilter{}, page, size)
+}
+
+// GetFiltered 按过滤条件分页获取用户，同时返回满足条件的用户总数
+func (r *UserRepository) GetFiltered(_ context.Context, filter models.UserFilter, page, size int) ([]*models.User, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("GetFiltered"); err != nil {
+		return nil, 0, err
+	}
+
+	var matched []*models.User
+	for _, u := range r.sorted() {
+		switch {
+		case filter.Username != "" && !containsFold(u.Username, filter.Username),
+			filter.NickName != "" && !containsFold(u.NickName, filter.NickName),
+			filter.Email != "" && !strings.EqualFold(u.Email, filter.Email),
+			filter.Status != "" && u.Status != filter.Status,
+			!filter.CreatedAfter.IsZero() && u.CreatedAt.Before(filter.CreatedAfter),
+			!filter.CreatedBefore.IsZero() && u.CreatedAt.After(filter.CreatedBefore):
+			continue
+		}
+		matched = append(matched, u)
+	}
+
+	total := int64(len(matched))
+	start := min((max(page, 1)-1)*size, len(matched))
+	end := min(start+size, len(matched))
+	return matched[start:end], total, nil
+}
+
+// Search 按字段模糊匹配用户（忽略大小写）
+func (r *UserRepository) Search(_ context.Context, query string, field string) ([]*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("Search"); err != nil {
+		return nil, err
+	}
+
+	var users []*models.User
+	for _, u := range r.sorted() {
+		var value string
+		switch field {
+		case "username":
+			value = u.Username
+		case "nick_name":
+			value = u.NickName
+		case "email":
+			value = u.Email
+		default:
+			return nil, apperror.New(400, apperror.InvalidSearchField)
+		}
+		if containsFold(value, query) {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// GetByID 根据 ID 获取用户
+func (r *UserRepository) GetByID(_ context.Context, id uint) (*models.User, error) {
+	if r.BeforeGetByID != nil {
+		r.BeforeGetByID()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("GetByID"); err != nil {
+		return nil, err
+	}
+	return r.find(func(u *models.User) bool { return u.ID == id })
+}
+
+// GetByIDFromPrimary 根据 ID 获取用户，内存仓库没有只读副本，与 GetByID 相同
+func (r *UserRepository) GetByIDFromPrimary(_ context.Context, id uint) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("GetByIDFromPrimary"); err != nil {
+		return nil, err
+	}
+	return r.find(func(u *models.User) bool { return u.ID == id })
+}
+
+// GetUserByUserName 根据用户名获取用户
+func (r *UserRepository) GetUserByUserName(_ context.Context, username string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("GetUserByUserName"); err != nil {
+		return nil, err
+	}
+	return r.find(func(u *models.User) bool { return u.Username == username })
+}
+
+// GetByEmailOrUsername 根据用户名或邮箱（忽略大小写）获取用户
+func (r *UserRepository) GetByEmailOrUsername(_ context.Context, value string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("GetByEmailOrUsername"); err != nil {
+		return nil, err
+	}
+	return r.find(func(u *models.User) bool { return u.Username == value || strings.EqualFold(u.Email, value) })
+}
+
+// GetByEmail 根据邮箱（忽略大小写）获取用户
+func (r *UserRepository) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("GetByEmail"); err != nil {
+		return nil, err
+	}
+	return r.find(func(u *models.User) bool { return strings.EqualFold(u.Email, email) })
+}
+
+// ExistsByEmail 判断邮箱是否已被使用
+func (r *UserRepository) ExistsByEmail(_ context.Context, email string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("ExistsByEmail"); err != nil {
+		return false, err
+	}
+	_, err := r.find(func(u *models.User) bool { return strings.EqualFold(u.Email, email) })
+	return err == nil, nil
+}
+
+// FindEmailDuplicates 查找忽略大小写后邮箱重复的用户
+func (r *UserRepository) FindEmailDuplicates(_ context.Context) ([]models.DuplicateGroup, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("FindEmailDuplicates"); err != nil {
+		return nil, err
+	}
+
+	byEmail := make(map[string][]uint)
+	for _, u := range r.sorted() {
+		email := strings.ToLower(u.Email)
+		byEmail[email] = append(byEmail[email], u.ID)
+	}
+	groups := []models.DuplicateGroup{}
+	for email, ids := range byEmail {
+		if len(ids) > 1 {
+			groups = append(groups, models.DuplicateGroup{Email: email, UserIDs: ids})
+		}
+	}
+	slices.SortFunc(groups, func(a, b models.DuplicateGroup) int { return cmp.Compare(a.Email, b.Email) })
+	return groups, nil
+}
+
+// Update 更新用户的指定字段，支持 nick_name、password、role、status
+func (r *UserRepository) Update(_ context.Context, id uint, fields map[string]any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("Update"); err != nil {
+		return err
+	}
+	u, ok := r.users[id]
+	if !ok {
+		return apperror.New(404, apperror.RecordNotFound)
+	}
+	for column, value := range fields {
+		switch column {
+		case "nick_name":
+			u.NickName = value.(string)
+		case "password":
+			u.Password = value.(string)
+		case "role":
+			u.Role = value.(string)
+		case "status":
+			u.Status = value.(string)
+		default:
+			panic("servicetest: unsupported column " + column)
+		}
+	}
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetStatus 修改用户账号状态
+func (r *UserRepository) SetStatus(_ context.Context, id uint, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("SetStatus"); err != nil {
+		return err
+	}
+	u, ok := r.users[id]
+	if !ok {
+		return apperror.New(404, apperror.RecordNotFound)
+	}
+	u.Status = status
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete 软删除用户，不存在或已删除时返回 404
+func (r *UserRepository) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("Delete"); err != nil {
+		return err
+	}
+	if _, ok := r.users[id]; !ok {
+		return apperror.New(404, apperror.RecordNotFound)
+	}
+	r.softDelete(id)
+	return nil
+}
+
+// DeleteBatch 批量软删除用户，返回实际删除的 ID
+func (r *UserRepository) DeleteBatch(_ context.Context, ids []uint) ([]uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.begin("DeleteBatch"); err != nil {
+		return nil, err
+	}
+	var deleted []uint
+	for _, id := range ids {
+		if _, ok := r.users[id]; ok {
+			r.softDelete(id)
+			deleted = append(deleted, id)
+		}
+	}
+	slices.Sort(deleted)
+	return deleted, nil
+}
+
+// WithAdvisoryLock 使用进程内互斥锁模拟 advisory lock，锁已被持有时不执行 fn
+func (r *UserRepository) WithAdvisoryLock(_ context.Context, _ int64, fn func() error) (bool, error) {
+	r.mu.Lock()
+	err := r.begin("WithAdvisoryLock")
+	r.mu.Unlock()
+	if err != nil {
+		return false, err
+	}
+	if !r.lock.TryLock() {
+		return false, nil
+	}
+	defer r.lock.Unlock()
+	return true, fn()
+}
+
+// containsFold 忽略大小写判断 s 是否包含 substr
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
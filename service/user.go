@@ -1,52 +1,149 @@
 package service
 
 import (
-	"gojet/dao"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gojet/config"
 	"gojet/models"
 	"gojet/util/apperror"
+	"gojet/util/messaging"
 	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // userRepo 包级变量，存储用户仓库实例
-var userRepo *dao.UserRepository
+var userRepo UserRepository
+
+// publisher 包级变量，用于发布用户生命周期事件
+var publisher messaging.Publisher = messaging.NoopPublisher{}
+
+// userGroup 合并同一用户的并发查询，避免缓存失效时大量请求同时击穿到数据库
+var userGroup singleflight.Group
 
-// InitService 初始化服务层，设置依赖的数据仓库
-func InitService(repo *dao.UserRepository) {
+// InitService 初始化服务层，设置依赖的数据仓库和消息发布者
+func InitService(repo UserRepository, pub messaging.Publisher) {
 	userRepo = repo
+	if pub != nil {
+		publisher = pub
+	}
+}
+
+// publishEvent 发布用户事件，发布失败只记录日志，不影响主流程
+func publishEvent(subject string, payload messaging.UserEvent) {
+	if err := publisher.Publish(subject, payload); err != nil {
+		slog.Error("发布用户事件失败", "subject", subject, "error", err)
+	}
 }
 
-// CreateUser 使用完整的用户信息创建用户
-func CreateUser(user *models.User) (*models.User, error) {
-	if err := userRepo.Create(user); err != nil {
+// CreateUser 使用完整的用户信息创建用户，user.Password 为明文密码
+// 邮箱格式在这里校验，HTTP 与 gRPC 等所有入口都会经过；
+// 先检查用户名和邮箱是否已存在再进行哈希，避免为注定失败的请求执行耗时的 bcrypt
+func CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	if err := models.ValidateEmail(user.Email); err != nil {
+		return nil, err
+	}
+
+	existing, err := userRepo.GetUserByUserName(ctx, user.Username)
+	if existing != nil {
+		return nil, apperror.New(409, apperror.UserExists)
+	}
+	var appErr *apperror.Error
+	if err != nil && !(errors.As(err, &appErr) && appErr.Code == 404) {
+		return nil, err
+	}
+
+	emailExists, err := userRepo.ExistsByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if emailExists {
+		return nil, apperror.New(409, apperror.EmailExists)
+	}
+
+	hashedPassword, err := models.HashPassword(user.Password)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = hashedPassword
+	// 注册与创建接口不允许客户端指定角色和状态，避免自行提升为管理员
+	user.Role = models.RoleUser
+	user.Status = models.StatusActive
+
+	if err := userRepo.Create(ctx, user); err != nil {
 		slog.Error("创建用户失败", "用户", user.Username, "error", err)
+		// 并发创建时唯一索引冲突由 DAO 返回 409，直接透传
+		var appErr *apperror.Error
+		if errors.As(err, &appErr) && appErr.Code == 409 {
+			return nil, err
+		}
 		return nil, apperror.Wrap(err, 500, apperror.UserCreateFailed)
 	}
 
 	slog.Info("创建用户成功", "id", user.ID, "username", user.Username)
+	publishEvent(messaging.SubjectUserCreated, messaging.NewUserEvent(user))
 	return user, nil
 }
 
+// seedOperator 初始数据的创建人/更新人
+const seedOperator = "system"
+
+// seedLockID 初始化数据使用的 advisory lock ID，多实例同时启动时只允许一个实例写入
+const seedLockID int64 = 7_340_001
+
 // CreateInitialData 创建初始学生数据
-func CreateInitialData() error {
-	existingUsers, err := userRepo.GetAll()
+func CreateInitialData(ctx context.Context) error {
+	if cfg != nil && !cfg.App.SeedEnabled {
+		slog.Info("初始数据已禁用，跳过插入")
+		return nil
+	}
+
+	acquired, err := userRepo.WithAdvisoryLock(ctx, seedLockID, func() error {
+		return createInitialData(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		slog.Info("其他实例正在初始化数据，跳过插入")
+	}
+	return nil
+}
+
+// createInitialData 检查并插入初始数据，调用方需持有 advisory lock
+func createInitialData(ctx context.Context) error {
+	_, existing, err := userRepo.GetAll(ctx, 1, 1)
 	if err != nil {
 		// 重要：遇到错误应该返回，而不是继续执行
+		slog.Error("检查现有数据失败", "error", err)
 		return apperror.Wrap(err, 500, "检查现有数据失败")
 	}
-	if len(existingUsers) > 0 {
+	if existing > 0 {
 		slog.Info("初始数据已存在，跳过插入")
 		return nil // 数据已存在，跳过
 	}
 
-	users := []*models.User{
-		{Username: "包子", NickName: "包子", Password: "123456", Email: "baozi@example.com"},
-		{Username: "玉米", NickName: "玉米", Password: "123456", Email: "corn@example.com"},
-		{Username: "花卷", NickName: "花卷", Password: "123456", Email: "flower@example.com"},
-		{Username: "吐司", NickName: "吐司", Password: "123456", Email: "toast@example.com"},
-	}
+	users := seedUsers()
 
-	// 对每个用户的密码进行哈希处理
+	// 对每个用户的密码进行哈希处理，并补充审计字段
 	for _, user := range users {
+		if user.CreatedBy == "" {
+			user.CreatedBy = seedOperator
+		}
+		if user.UpdatedBy == "" {
+			user.UpdatedBy = seedOperator
+		}
+		if user.Role == "" {
+			user.Role = models.RoleUser
+		}
+		if user.Status == "" {
+			user.Status = models.StatusActive
+		}
 		hashedPassword, err := models.HashPassword(user.Password)
 		if err != nil {
 			slog.Error("密码哈希失败", "username", user.Username, "error", err)
@@ -55,7 +152,7 @@ func CreateInitialData() error {
 		user.Password = hashedPassword
 	}
 
-	if err := userRepo.CreateBatch(users); err != nil {
+	if err := userRepo.CreateBatch(ctx, users); err != nil {
 		slog.Error("创建初始数据失败", "error", err)
 		return apperror.Wrap(err, 500, apperror.DBInsertError)
 	}
@@ -64,49 +161,168 @@ func CreateInitialData() error {
 	return nil
 }
 
-// GetAllUsers 获取所有用户
-func GetAllUsers() ([]*models.User, error) {
-	users, err := userRepo.GetAll()
+// seedUsers 获取初始数据用户列表
+// 设置了 SEED_USERS_JSON 环境变量时使用其中的用户，解析失败时回退到默认数据
+func seedUsers() []*models.User {
+	if val := os.Getenv("SEED_USERS_JSON"); val != "" {
+		var users []*models.User
+		if err := json.Unmarshal([]byte(val), &users); err != nil {
+			slog.Warn("解析 SEED_USERS_JSON 失败，使用默认初始数据", "error", err)
+		} else {
+			return users
+		}
+	}
+
+	return []*models.User{
+		{Username: "包子", NickName: "包子", Password: "123456", Email: "baozi@example.com", Role: models.RoleAdmin},
+		{Username: "玉米", NickName: "玉米", Password: "123456", Email: "corn@example.com"},
+		{Username: "花卷", NickName: "花卷", Password: "123456", Email: "flower@example.com"},
+		{Username: "吐司", NickName: "吐司", Password: "123456", Email: "toast@example.com"},
+	}
+}
+
+// 分页参数默认值与上限
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 200
+)
+
+// GetAllUsers 分页获取所有用户，返回当前页用户和用户总数
+func GetAllUsers(ctx context.Context, page, size int) ([]*models.User, int64, error) {
+	users, total, err := userRepo.GetAll(ctx, page, size)
 	if err != nil {
-		return nil, apperror.Wrap(err, 500, "获取用户列表失败")
+		return nil, 0, apperror.Wrap(err, 500, "获取用户列表失败")
 	}
-	return users, nil
+	return users, total, nil
 }
 
-// GetUserByID 根据 ID 获取用户
-func GetUserByID(id uint) (*models.User, error) {
-	user, err := userRepo.GetByID(id)
+// GetFilteredUsers 按过滤条件分页获取用户列表，返回当前页用户和满足条件的用户总数
+func GetFilteredUsers(ctx context.Context, filter models.UserFilter, page, size int) ([]*models.User, int64, error) {
+	users, total, err := userRepo.GetFiltered(ctx, filter, page, size)
 	if err != nil {
-		// DAO 层已经包装了错误，直接返回
-		return nil, err
+		return nil, 0, apperror.Wrap(err, 500, "获取用户列表失败")
 	}
-	return user, nil
+	return users, total, nil
 }
 
-// UpdateUser 更新用户信息
-func UpdateUser(id uint, name string) (*models.User, error) {
-	user, err := userRepo.GetByID(id)
+// SearchUsers 按字段（username/nick_name/email）模糊搜索用户
+func SearchUsers(ctx context.Context, query, field string) ([]*models.User, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, apperror.New(400, apperror.InvalidParams)
+	}
+	users, err := userRepo.Search(ctx, query, field)
 	if err != nil {
+		return nil, apperror.PassThrough(err, 500, "搜索用户失败")
+	}
+	return users, nil
+}
+
+// FindDuplicates 查找邮箱重复（忽略大小写）的用户，用于数据完整性审计
+func FindDuplicates(ctx context.Context) ([]models.DuplicateGroup, error) {
+	groups, err := userRepo.FindEmailDuplicates(ctx)
+	if err != nil {
+		slog.Error("查找重复邮箱失败", "error", err)
 		return nil, err
 	}
+	return groups, nil
+}
 
-	user.Username = name
+// GetUserByID 根据 ID 获取用户
+func GetUserByID(ctx context.Context, id uint) (*models.User, error) {
+	// 合并后的查询由多个请求共享，不能因为第一个请求取消而让其他请求一起失败
+	sharedCtx := context.WithoutCancel(ctx)
+	v, err, _ := userGroup.Do(fmt.Sprintf("user:%d", id), func() (any, error) {
+		return userRepo.GetByID(sharedCtx, id)
+	})
+	if err != nil {
+		return nil, apperror.PassThrough(err, 500, apperror.DBQueryError)
+	}
+	return v.(*models.User), nil
+}
 
-	if err := userRepo.Update(user); err != nil {
+// UpdateUser 更新用户昵称（显示名称），登录名 Username 不允许修改
+// 只更新 nick_name 列，并从主库读取更新后的用户返回
+func UpdateUser(ctx context.Context, id uint, nickName string) (*models.User, error) {
+	if err := userRepo.Update(ctx, id, map[string]any{"nick_name": nickName}); err != nil {
 		slog.Error("更新用户失败", "id", id, "error", err)
-		return nil, apperror.Wrap(err, 500, apperror.UserUpdateFailed)
+		// 用户不存在时 DAO 返回 404，直接透传
+		return nil, apperror.PassThrough(err, 500, apperror.UserUpdateFailed)
 	}
 
-	slog.Info("更新用户成功", "id", id, "name", name)
+	user, err := userRepo.GetByIDFromPrimary(ctx, id)
+	if err != nil {
+		return nil, apperror.PassThrough(err, 500, apperror.DBQueryError)
+	}
+
+	slog.Info("更新用户成功", "id", id, "nick_name", nickName)
+	publishEvent(messaging.SubjectUserUpdated, messaging.NewUserEvent(user))
 	return user, nil
 }
 
+// SetUserStatus 启用或停用用户账号
+// 停用后用户无法登录或刷新 token，已签发的 accessToken 在过期前仍然有效
+func SetUserStatus(ctx context.Context, id uint, status string) error {
+	if err := userRepo.SetStatus(ctx, id, status); err != nil {
+		slog.Error("修改用户状态失败", "id", id, "status", status, "error", err)
+		return apperror.PassThrough(err, 500, apperror.UserUpdateFailed)
+	}
+
+	slog.Info("修改用户状态成功", "id", id, "status", status)
+	publishEvent(messaging.SubjectUserUpdated, messaging.UserEvent{ID: id, Status: status})
+	return nil
+}
+
+// DeleteUsersResp 批量删除结果
+type DeleteUsersResp struct {
+	Deleted  int    `json:"deleted"`   // 实际删除的用户数
+	NotFound []uint `json:"not_found"` // 不存在（或已被删除）的用户ID
+}
+
+// DeleteUsers 批量删除用户，ID 去重后数量不能超过 app.max_batch_delete
+// 部分 ID 不存在时不视为失败，在结果中返回这些 ID
+func DeleteUsers(ctx context.Context, ids []uint) (*DeleteUsersResp, error) {
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	if len(ids) == 0 {
+		return nil, apperror.New(400, "待删除的用户ID不能为空")
+	}
+	if ids[0] == 0 {
+		return nil, apperror.New(400, apperror.InvalidUserID)
+	}
+	limit := (&config.AppConfig{}).GetMaxBatchDelete()
+	if cfg != nil {
+		limit = cfg.App.GetMaxBatchDelete()
+	}
+	if len(ids) > limit {
+		return nil, apperror.New(400, fmt.Sprintf("一次最多删除 %d 个用户", limit))
+	}
+
+	deleted, err := userRepo.DeleteBatch(ctx, ids)
+	if err != nil {
+		slog.Error("批量删除用户失败", "ids", ids, "error", err)
+		return nil, apperror.PassThrough(err, 500, apperror.UserDeleteFailed)
+	}
+
+	resp := &DeleteUsersResp{Deleted: len(deleted), NotFound: []uint{}}
+	for _, id := range ids {
+		if !slices.Contains(deleted, id) {
+			resp.NotFound = append(resp.NotFound, id)
+		}
+	}
+	for _, id := range deleted {
+		publishEvent(messaging.SubjectUserDeleted, messaging.UserEvent{ID: id})
+	}
+	slog.Info("批量删除用户成功", "deleted", deleted, "not_found", resp.NotFound)
+	return resp, nil
+}
+
 // DeleteUser 删除用户
-func DeleteUser(id uint) error {
-	if err := userRepo.Delete(id); err != nil {
+func DeleteUser(ctx context.Context, id uint) error {
+	if err := userRepo.Delete(ctx, id); err != nil {
 		slog.Error("删除用户失败", "id", id, "error", err)
-		return apperror.Wrap(err, 500, apperror.UserDeleteFailed)
+		// DAO 层已返回 AppError（例如 404）时直接透传，避免被改写为 500
+		return apperror.PassThrough(err, 500, apperror.UserDeleteFailed)
 	}
 	slog.Info("删除用户成功", "id", id)
+	publishEvent(messaging.SubjectUserDeleted, messaging.UserEvent{ID: id})
 	return nil
 }
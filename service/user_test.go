@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"gojet/models"
+	"gojet/service/servicetest"
+	"gojet/util/apperror"
+	"gojet/util/messaging"
+)
+
+func TestUserEventsOmitPassword(t *testing.T) {
+	setup(t)
+	pub := &recordingPublisher{}
+	publisher = pub
+	ctx := context.Background()
+
+	user, err := CreateUser(ctx, &models.User{Username: "alice", NickName: "Alice", Password: "secret123", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := UpdateUser(ctx, user.ID, "Alice L"); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+
+	wantSubjects := []string{messaging.SubjectUserCreated, messaging.SubjectUserUpdated}
+	if len(pub.messages) != len(wantSubjects) {
+		t.Fatalf("published %d messages, want %d", len(pub.messages), len(wantSubjects))
+	}
+	for i, msg := range pub.messages {
+		if msg.subject != wantSubjects[i] {
+			t.Errorf("message %d subject = %q, want %q", i, msg.subject, wantSubjects[i])
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(msg.data, &fields); err != nil {
+			t.Fatalf("message %d: %v", i, err)
+		}
+		if _, ok := fields["password"]; ok {
+			t.Errorf("message %d contains password: %s", i, msg.data)
+		}
+		for _, key := range []string{"id", "username", "nick_name", "email", "role", "status"} {
+			if _, ok := fields[key]; !ok {
+				t.Errorf("message %d missing %q: %s", i, key, msg.data)
+			}
+		}
+	}
+}
+
+func TestUpdateUserWritesOnlyNickName(t *testing.T) {
+	repo := setup(t, &models.User{ID: 1, Username: "alice", NickName: "Alice", Email: "alice@example.com", Password: "hash", Role: models.RoleUser, Status: models.StatusActive})
+	ctx := context.Background()
+
+	// 模拟读取后其他请求修改了状态，更新昵称不应覆盖该状态
+	if err := repo.SetStatus(ctx, 1, models.StatusInactive); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+	user, err := UpdateUser(ctx, 1, "Alice L")
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if user.NickName != "Alice L" || user.Status != models.StatusInactive {
+		t.Errorf("user = {NickName: %q, Status: %q}, want {Alice L, %s}", user.NickName, user.Status, models.StatusInactive)
+	}
+	if n := repo.Calls("GetByID"); n != 0 {
+		t.Errorf("GetByID (replica) called %d times, want 0", n)
+	}
+}
+
+func TestGetUserByIDDeduplicatesConcurrentMisses(t *testing.T) {
+	repo := setup(t, &models.User{ID: 1, Username: "alice", Email: "alice@example.com", Password: "hash"})
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	repo.BeforeGetByID = func() {
+		once.Do(func() { close(entered) })
+		<-release
+	}
+
+	const n = 20
+	var ready, done sync.WaitGroup
+	errs := make(chan error, n)
+	call := func() {
+		defer done.Done()
+		ready.Done()
+		_, err := GetUserByID(context.Background(), 1)
+		errs <- err
+	}
+
+	// 第一个请求进入查询后阻塞，其余请求在其返回前到达
+	ready.Add(n)
+	done.Add(n)
+	go call()
+	<-entered
+	for range n - 1 {
+		go call()
+	}
+	ready.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	done.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+	}
+	if calls := repo.Calls("GetByID"); calls != 1 {
+		t.Errorf("GetByID called %d times, want 1", calls)
+	}
+}
+
+func TestCreateInitialDataConcurrent(t *testing.T) {
+	repo := setup(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for range 2 {
+		wg.Go(func() {
+			errs <- CreateInitialData(context.Background())
+		})
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("CreateInitialData: %v", err)
+		}
+	}
+	if got, want := len(repo.Users()), len(seedUsers()); got != want {
+		t.Errorf("seeded %d users, want %d", got, want)
+	}
+}
+
+func TestCreateUserEmailPreCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		wantCode    int
+		wantCreates int
+	}{
+		{"邮箱已被注册", "alice@example.com", 409, 0},
+		{"邮箱可用", "bob@example.com", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := setup(t, &models.User{Username: "alice", Email: "alice@example.com", Password: "hash"})
+
+			_, err := CreateUser(context.Background(), &models.User{Username: "bob", NickName: "Bob", Email: tt.email, Password: "secret123"})
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("CreateUser: %v", err)
+				}
+			} else {
+				var appErr *apperror.Error
+				if !errors.As(err, &appErr) || appErr.Code != tt.wantCode || appErr.Message != apperror.EmailExists {
+					t.Fatalf("err = %v, want %d %s", err, tt.wantCode, apperror.EmailExists)
+				}
+			}
+			if n := repo.Calls("Create"); n != tt.wantCreates {
+				t.Errorf("Create called %d times, want %d", n, tt.wantCreates)
+			}
+		})
+	}
+}
+
+func TestDeleteUsersPartialFailure(t *testing.T) {
+	users := func() []*models.User {
+		return []*models.User{
+			{ID: 1, Username: "alice", Email: "alice@example.com", Password: "hash"},
+			{ID: 2, Username: "bob", Email: "bob@example.com", Password: "hash"},
+			{ID: 3, Username: "carol", Email: "carol@example.com", Password: "hash"},
+		}
+	}
+	tooMany := make([]uint, 101)
+	for i := range tooMany {
+		tooMany[i] = uint(i + 1)
+	}
+
+	tests := []struct {
+		name         string
+		ids          []uint
+		before       func(repo *servicetest.UserRepository)
+		wantCode     int
+		wantDeleted  int
+		wantNotFound []uint
+	}{
+		{"全部存在", []uint{1, 2}, nil, 0, 2, []uint{}},
+		{"部分不存在", []uint{3, 1, 99}, nil, 0, 2, []uint{99}},
+		{"全部不存在", []uint{98, 99}, nil, 0, 0, []uint{98, 99}},
+		{"重复 ID 只删除一次", []uint{1, 1, 2}, nil, 0, 2, []uint{}},
+		{"已删除的用户视为不存在", []uint{1, 2}, func(repo *servicetest.UserRepository) {
+			if err := repo.Delete(context.Background(), 1); err != nil {
+				t.Fatal(err)
+			}
+		}, 0, 1, []uint{1}},
+		{"数据库错误", []uint{1, 2}, func(repo *servicetest.UserRepository) {
+			repo.FailOn("DeleteBatch", errors.New("connection reset"))
+		}, 500, 0, nil},
+		{"空列表", []uint{}, nil, 400, 0, nil},
+		{"包含无效 ID", []uint{0, 1}, nil, 400, 0, nil},
+		{"超过数量上限", tooMany, nil, 400, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := setup(t, users()...)
+			if tt.before != nil {
+				tt.before(repo)
+			}
+
+			resp, err := DeleteUsers(context.Background(), tt.ids)
+			if tt.wantCode != 0 {
+				var appErr *apperror.Error
+				if !errors.As(err, &appErr) || appErr.Code != tt.wantCode {
+					t.Fatalf("err = %v, want %d", err, tt.wantCode)
+				}
+				// 参数校验失败时不访问数据库
+				if tt.wantCode == 400 && repo.Calls("DeleteBatch") != 0 {
+					t.Error("DeleteBatch called for invalid ids")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DeleteUsers: %v", err)
+			}
+			if resp.Deleted != tt.wantDeleted || !slices.Equal(resp.NotFound, tt.wantNotFound) {
+				t.Errorf("resp = %+v, want deleted %d, not_found %v", resp, tt.wantDeleted, tt.wantNotFound)
+			}
+		})
+	}
+}
+
+func TestDeletedUserIsHiddenButKeepsUniqueKeys(t *testing.T) {
+	setup(t, &models.User{ID: 1, Username: "alice", NickName: "Alice", Email: "alice@example.com", Password: "hash"})
+	ctx := context.Background()
+
+	if err := DeleteUser(ctx, 1); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	var appErr *apperror.Error
+	if _, err := GetUserByID(ctx, 1); !errors.As(err, &appErr) || appErr.Code != 404 {
+		t.Errorf("GetUserByID after delete: err = %v, want 404", err)
+	}
+	if err := DeleteUser(ctx, 1); !errors.As(err, &appErr) || appErr.Code != 404 {
+		t.Errorf("second DeleteUser: err = %v, want 404", err)
+	}
+	// 软删除的记录仍在唯一索引中，用户名不能被重新注册
+	_, err := CreateUser(ctx, &models.User{Username: "alice", NickName: "Alice", Email: "new@example.com", Password: "secret123"})
+	if !errors.As(err, &appErr) || appErr.Code != 409 {
+		t.Errorf("CreateUser with deleted username: err = %v, want 409", err)
+	}
+}
+
+func TestCreateUserValidatesEmail(t *testing.T) {
+	for _, email := range []string{"", "not-an-email", "alice@", "@example.com", "Alice <alice@example.com>"} {
+		repo := setup(t)
+		_, err := CreateUser(context.Background(), &models.User{Username: "alice", NickName: "Alice", Email: email, Password: "secret123"})
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Code != 422 || appErr.Message != apperror.InvalidEmail {
+			t.Errorf("CreateUser(email=%q) err = %v, want 422 %s", email, err, apperror.InvalidEmail)
+		}
+		// 格式校验在查询数据库之前
+		if n := repo.Calls("GetUserByUserName") + repo.Calls("Create"); n != 0 {
+			t.Errorf("email %q: repository called %d times", email, n)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gojet/config"
+	"gojet/util/logging"
+	"gojet/util/middleware"
+	"gojet/util/response"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestFileWriterCreatesFile(t *testing.T) {
+	cfg := config.DefaultConfig().Logging
+	cfg.FilePath = filepath.Join(t.TempDir(), "logs", "app.log")
+
+	w, err := fileWriter(cfg)
+	if err != nil {
+		t.Fatalf("fileWriter: %v", err)
+	}
+	// 目录与文件在创建时即存在，无需等待首次写入
+	if _, err := os.Stat(cfg.FilePath); err != nil {
+		t.Fatalf("日志文件未创建: %v", err)
+	}
+	if _, err := w.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(cfg.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "hello") {
+		t.Errorf("file content = %q, want hello", data)
+	}
+}
+
+func TestFileWriterRotates(t *testing.T) {
+	cfg := config.DefaultConfig().Logging
+	cfg.FilePath = filepath.Join(t.TempDir(), "app.log")
+	cfg.MaxSizeMB = 1
+
+	w, err := fileWriter(cfg)
+	if err != nil {
+		t.Fatalf("fileWriter: %v", err)
+	}
+	defer w.Close()
+
+	// 两次写入合计超过 1MB，第二次写入前切割
+	chunk := bytes.Repeat([]byte("x"), 600*1024)
+	for range 2 {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(cfg.FilePath), "app-*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("rotated files = %v, want 1", backups)
+	}
+	info, err := os.Stat(cfg.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(len(chunk)) {
+		t.Errorf("current log size = %d, want %d", info.Size(), len(chunk))
+	}
+}
+
+func TestConfigurePool(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.DatabaseConfig
+		wantMaxOpen int
+	}{
+		{"未配置使用默认值", config.DatabaseConfig{}, 25},
+		{"自定义", config.DatabaseConfig{MaxOpenConns: 50, MaxIdleConns: 5, ConnMaxLifetimeSeconds: 60}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, _, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sqlDB.Close()
+			db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := configurePool(db, tt.cfg); err != nil {
+				t.Fatalf("configurePool: %v", err)
+			}
+			// sql.DBStats 只暴露最大打开连接数，空闲连接数与复用时间由 config 包的测试覆盖
+			if got := sqlDB.Stats().MaxOpenConnections; got != tt.wantMaxOpen {
+				t.Errorf("MaxOpenConnections = %d, want %d", got, tt.wantMaxOpen)
+			}
+		})
+	}
+}
+
+func TestPoolMonitorCheck(t *testing.T) {
+	monitor := &poolMonitor{waitThreshold: 10}
+	tests := []struct {
+		name      string
+		stats     sql.DBStats
+		wantWaits int64
+		wantWait  bool
+		wantUsage bool
+	}{
+		{"空闲", sql.DBStats{MaxOpenConnections: 10, OpenConnections: 2, WaitCount: 5}, 5, false, false},
+		{"本周期等待过多", sql.DBStats{MaxOpenConnections: 10, OpenConnections: 9, WaitCount: 20}, 15, true, true},
+		// 累计值仍高于阈值，但本周期内没有新增等待，不应继续告警
+		{"压力解除", sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3, WaitCount: 20}, 0, false, false},
+		{"未限制最大连接数", sql.DBStats{OpenConnections: 50, WaitCount: 25}, 5, false, false},
+	}
+	for _, tt := range tests {
+		waits, waitExceeded, usageExceeded := monitor.check(tt.stats)
+		if waits != tt.wantWaits || waitExceeded != tt.wantWait || usageExceeded != tt.wantUsage {
+			t.Errorf("%s: check() = (%d, %v, %v), want (%d, %v, %v)",
+				tt.name, waits, waitExceeded, usageExceeded, tt.wantWaits, tt.wantWait, tt.wantUsage)
+		}
+	}
+}
+
+func TestLogFormatFromEnv(t *testing.T) {
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("JWT_SECRET", "test-secret")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		format   string
+		wantJSON bool
+	}{
+		{"text", false},
+		{"json", true},
+	} {
+		t.Setenv("LOG_FORMAT", tt.format)
+		cfg, err := config.LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig: %v", err)
+		}
+
+		var buf bytes.Buffer
+		slog.New(newLogHandler(&buf, cfg.Logging.Format, slog.LevelInfo)).Info("hello", "user_id", 1)
+		line := buf.String()
+		if got := json.Valid(buf.Bytes()); got != tt.wantJSON {
+			t.Errorf("LOG_FORMAT=%s: JSON output = %v, want %v: %s", tt.format, got, tt.wantJSON, line)
+		}
+		if !tt.wantJSON && (!strings.Contains(line, "msg=hello") || !strings.Contains(line, "user_id=1")) {
+			t.Errorf("LOG_FORMAT=text: output is not key=value text: %s", line)
+		}
+	}
+}
+
+func TestRequestIDRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	defaultLogger := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	r := gin.New()
+	r.Use(middleware.RequestID(), loggingMiddleware(logger, nil))
+	r.GET("/ok", func(c *gin.Context) {
+		logging.LoggerFromContext(c.Request.Context()).Info("handler")
+		response.Success(c, "", nil)
+	})
+	r.GET("/fail", func(c *gin.Context) {
+		response.BadRequest(c, "bad")
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		wantEcho bool // 是否沿用请求头中的 ID
+	}{
+		{"沿用请求头", "/ok", "client-id-1", true},
+		{"自动生成", "/ok", "", false},
+		{"请求头过长", "/ok", strings.Repeat("a", 129), false},
+		{"错误响应", "/fail", "client-id-2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(middleware.RequestIDHeader)
+			if tt.wantEcho && id != tt.header {
+				t.Fatalf("response header = %q, want %q", id, tt.header)
+			}
+			if !tt.wantEcho {
+				if _, err := uuid.Parse(id); err != nil {
+					t.Fatalf("response header = %q, want generated UUID", id)
+				}
+			}
+
+			var resp response.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.RequestID != id {
+				t.Errorf("body request_id = %q, want %q", resp.RequestID, id)
+			}
+
+			// 处理函数日志与访问日志都带有同一个请求 ID
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			for _, line := range lines {
+				var entry map[string]any
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("invalid log line %q: %v", line, err)
+				}
+				if entry[logging.RequestIDKey] != id {
+					t.Errorf("log %q: request_id = %v, want %q", entry["msg"], entry[logging.RequestIDKey], id)
+				}
+			}
+			wantLines := 1
+			if tt.path == "/ok" {
+				wantLines = 2
+			}
+			if len(lines) != wantLines {
+				t.Errorf("got %d log lines, want %d: %s", len(lines), wantLines, buf.String())
+			}
+		})
+	}
+}
+
+func TestDialector(t *testing.T) {
+	for _, tt := range []struct{ driver, want string }{
+		{config.DriverPostgres, "postgres"},
+		{config.DriverMySQL, "mysql"},
+	} {
+		if got := dialector(tt.driver, "").Name(); got != tt.want {
+			t.Errorf("dialector(%q).Name() = %q, want %q", tt.driver, got, tt.want)
+		}
+	}
+}
+
+func TestGormConfigTranslatesErrors(t *testing.T) {
+	// 主库与只读副本各自打开连接，唯一约束冲突都应转换为 gorm.ErrDuplicatedKey
+	for _, name := range []string{"主库", "只读副本"} {
+		t.Run(name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sqlDB.Close()
+			db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), gormConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			mock.ExpectExec("INSERT").WillReturnError(&pgconn.PgError{Code: "23505"})
+
+			err = db.Exec("INSERT INTO users (username) VALUES ('alice')").Error
+			if !errors.Is(err, gorm.ErrDuplicatedKey) {
+				t.Errorf("err = %v, want gorm.ErrDuplicatedKey", err)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package apperror
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // Error 是应用层统一错误类型，包含业务码和用户可读信息
 type Error struct {
@@ -10,10 +13,11 @@ type Error struct {
 }
 
 func (e *Error) Error() string {
+	// 包含错误码，便于在日志中按错误码检索
 	if e.Err != nil {
-		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+		return fmt.Sprintf("[%d] %s: %v", e.Code, e.Message, e.Err)
 	}
-	return e.Message
+	return fmt.Sprintf("[%d] %s", e.Code, e.Message)
 }
 
 // Unwrap 使 errors.Is / As 能够访问底层错误
@@ -28,3 +32,16 @@ func New(code int, message string) *Error {
 func Wrap(err error, code int, message string) *Error {
 	return &Error{Code: code, Message: message, Err: err}
 }
+
+// PassThrough 已是 AppError 的错误原样返回，保留下层设置的状态码（例如 404）；
+// 其余错误使用 code/message 包装。err 为 nil 时返回 nil
+func PassThrough(err error, code int, message string) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return Wrap(err, code, message)
+}
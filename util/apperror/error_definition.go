@@ -2,11 +2,15 @@ package apperror
 
 const (
 	// 通用错误
-	InvalidParams   = "请求参数无效"
-	InternalError   = "服务器内部错误"
-	DatabaseError   = "数据库操作失败"
-	RecordNotFound  = "记录不存在"
-	OperationFailed = "操作失败"
+	InvalidParams    = "请求参数无效"
+	InternalError    = "服务器内部错误"
+	DatabaseError    = "数据库操作失败"
+	RecordNotFound   = "记录不存在"
+	OperationFailed  = "操作失败"
+	PayloadTooLarge  = "请求体过大"
+	TooManyRequests  = "请求过于频繁，请稍后再试"
+	Conflict         = "资源冲突"
+	ValidationFailed = "参数校验失败"
 
 	// 用户相关错误
 	UserNotFound     = "用户不存在"
@@ -14,6 +18,19 @@ const (
 	UserUpdateFailed = "用户更新失败"
 	UserDeleteFailed = "用户删除失败"
 	InvalidUserID    = "无效的用户 ID"
+	UserExists       = "用户名已存在"
+	UserDuplicate    = "用户名或邮箱已存在"
+	EmailExists      = "邮箱已被注册"
+	InvalidEmail     = "邮箱格式不正确"
+	AccountDisabled  = "账号已被停用"
+
+	InvalidSearchField = "不支持的搜索字段"
+
+	// 密码相关错误
+	PasswordTooLong    = "密码超过最大长度限制（72字节）"
+	PasswordHashFailed = "密码加密失败"
+	PasswordTooWeak    = "密码至少 8 位且必须包含数字"
+	OldPasswordWrong   = "原密码错误"
 
 	// 数据库相关错误
 	DBQueryError  = "数据查询失败"
@@ -22,9 +39,16 @@ const (
 	DBDeleteError = "数据删除失败"
 
 	// 认证相关错误
-	AuthFailed   = "认证失败"
-	Unauthorized = "未授权访问"
-	TokenMissing = "令牌缺失"
-	TokenExpired = "令牌已过期"
-	TokenInvalid = "无效的令牌"
+	AuthFailed       = "认证失败"
+	Unauthorized     = "未授权访问"
+	TokenMissing     = "令牌缺失"
+	TokenExpired     = "令牌已过期"
+	TokenInvalid     = "无效的令牌"
+	TokenNotValidYet = "令牌尚未生效"
+	PermissionDenied = "权限不足"
+	TokenRevoked     = "令牌已注销，请重新登录"
+	TokenCheckFailed = "令牌状态校验失败，请稍后再试"
+
+	RefreshTokenInvalid       = "刷新令牌无效、已过期或已使用"
+	PasswordResetTokenInvalid = "密码重置令牌无效、已过期或已使用"
 )
@@ -0,0 +1,60 @@
+package binding
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gojet/util/apperror"
+
+	"github.com/gin-gonic/gin"
+	ginbinding "github.com/gin-gonic/gin/binding"
+)
+
+// StrictBind 严格解析 JSON 请求体 - 存在未知字段时返回 400，而不是像 ShouldBindJSON 那样静默忽略
+// 解析成功后仍会执行 binding 标签校验
+func StrictBind(c *gin.Context, obj any) error {
+	if c.Request.Body == nil {
+		return apperror.New(400, apperror.InvalidParams)
+	}
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return decodeError(err)
+	}
+
+	if err := ginbinding.Validator.ValidateStruct(obj); err != nil {
+		return apperror.Wrap(err, 400, apperror.InvalidParams)
+	}
+	return nil
+}
+
+// decodeError 将 JSON 解析错误转换为带有描述信息的 AppError
+func decodeError(err error) error {
+	var (
+		maxBytesErr *http.MaxBytesError
+		typeErr     *json.UnmarshalTypeError
+		invalidErr  *json.InvalidUnmarshalError
+		syntaxErr   *json.SyntaxError
+	)
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return apperror.Wrap(err, 413, apperror.PayloadTooLarge)
+	case errors.As(err, &typeErr):
+		return apperror.Wrap(err, 400, fmt.Sprintf("字段 %s 类型错误，应为 %s", typeErr.Field, typeErr.Type))
+	case errors.As(err, &invalidErr):
+		return apperror.Wrap(err, 400, fmt.Sprintf("无法解析到类型 %v", invalidErr.Type))
+	case errors.As(err, &syntaxErr):
+		return apperror.Wrap(err, 400, fmt.Sprintf("JSON 格式错误（位置 %d）", syntaxErr.Offset))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json 未导出未知字段错误类型，只能通过错误信息判断
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return apperror.Wrap(err, 400, fmt.Sprintf("请求包含未知字段: %s", field))
+	default:
+		return apperror.Wrap(err, 400, apperror.InvalidParams)
+	}
+}
@@ -0,0 +1,70 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError 单个字段的校验错误，作为 422 响应的 data 返回给客户端
+type FieldError struct {
+	Field   string `json:"field"`   // JSON 字段名
+	Rule    string `json:"rule"`    // 未通过的校验规则，例如 required、email
+	Message string `json:"message"` // 可读的错误描述
+}
+
+// ruleMessages 常用校验规则的错误描述
+var ruleMessages = map[string]string{
+	"required": "不能为空",
+	"email":    "邮箱格式不正确",
+	"min":      "长度或数值过小",
+	"max":      "长度或数值过大",
+	"oneof":    "取值不在允许范围内",
+}
+
+// FieldErrors 将 binding 标签校验失败的错误转换为字段错误列表，obj 为绑定的目标结构体指针
+// err 不是校验错误（例如 JSON 格式错误）时返回 nil
+func FieldErrors(obj any, err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		message, ok := ruleMessages[fe.Tag()]
+		if !ok {
+			message = fmt.Sprintf("未通过 %s 校验", fe.Tag())
+		}
+		fields = append(fields, FieldError{
+			Field:   jsonName(t, fe.StructField()),
+			Rule:    fe.Tag(),
+			Message: message,
+		})
+	}
+	return fields
+}
+
+// jsonName 获取结构体字段的 JSON 名称，找不到字段或未设置 json 标签时返回字段名
+func jsonName(t reflect.Type, field string) string {
+	if t.Kind() != reflect.Struct {
+		return field
+	}
+	sf, ok := t.FieldByName(field)
+	if !ok {
+		return field
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field
+	}
+	return name
+}
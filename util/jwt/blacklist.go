@@ -0,0 +1,139 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Blacklist 已吊销 token 黑名单，按 jti 记录，条目在 token 原本的过期时间后失效
+type Blacklist interface {
+	Revoke(jti string, ttl time.Duration) error
+	IsRevoked(jti string) (bool, error)
+	Close() error
+}
+
+// sweepInterval 进程内黑名单清理过期条目的最小间隔
+const sweepInterval = time.Minute
+
+// MemoryBlacklist 进程内黑名单，仅适用于单实例部署，重启后失效
+type MemoryBlacklist struct {
+	entries   sync.Map // jti -> 过期时间
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewMemoryBlacklist 创建进程内黑名单
+func NewMemoryBlacklist() *MemoryBlacklist {
+	return &MemoryBlacklist{lastSweep: time.Now()}
+}
+
+// Revoke 吊销 token，ttl 不大于 0 时 token 已过期，无需记录
+func (b *MemoryBlacklist) Revoke(jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	b.entries.Store(jti, time.Now().Add(ttl))
+	b.sweep()
+	return nil
+}
+
+// IsRevoked 判断 token 是否已吊销
+func (b *MemoryBlacklist) IsRevoked(jti string) (bool, error) {
+	v, ok := b.entries.Load(jti)
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(v.(time.Time)) {
+		b.entries.Delete(jti)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Close 清空黑名单
+func (b *MemoryBlacklist) Close() error {
+	b.entries.Clear()
+	return nil
+}
+
+// sweep 定期移除已过期的条目，避免黑名单无限增长
+func (b *MemoryBlacklist) sweep() {
+	b.mu.Lock()
+	now := time.Now()
+	if now.Sub(b.lastSweep) < sweepInterval {
+		b.mu.Unlock()
+		return
+	}
+	b.lastSweep = now
+	b.mu.Unlock()
+
+	b.entries.Range(func(key, value any) bool {
+		if now.After(value.(time.Time)) {
+			b.entries.Delete(key)
+		}
+		return true
+	})
+}
+
+// redisTimeout 单次 Redis 操作超时时间
+const redisTimeout = 200 * time.Millisecond
+
+// RedisBlacklist 基于 Redis 的黑名单，多个实例共享，条目使用 Redis 过期时间自动清理
+type RedisBlacklist struct {
+	client *redis.Client
+}
+
+// NewRedisBlacklist 连接 Redis 并创建黑名单，连接失败时返回错误
+func NewRedisBlacklist(addr, password string, db int) (*RedisBlacklist, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("连接 Redis 失败: %w", err)
+	}
+	return &RedisBlacklist{client: client}, nil
+}
+
+// key 黑名单键
+func (b *RedisBlacklist) key(jti string) string {
+	return "gojet:jwt:revoked:" + jti
+}
+
+// Revoke 吊销 token，ttl 不大于 0 时 token 已过期，无需记录
+func (b *RedisBlacklist) Revoke(jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := b.client.Set(ctx, b.key(jti), 1, ttl).Err(); err != nil {
+		return fmt.Errorf("写入 token 黑名单失败: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked 判断 token 是否已吊销
+func (b *RedisBlacklist) IsRevoked(jti string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	n, err := b.client.Exists(ctx, b.key(jti)).Result()
+	if err != nil {
+		return false, fmt.Errorf("查询 token 黑名单失败: %w", err)
+	}
+	return n > 0, nil
+}
+
+// Close 关闭 Redis 连接
+func (b *RedisBlacklist) Close() error {
+	return b.client.Close()
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"errors"
 	"gojet/util/apperror"
 	"gojet/util/response"
 	"strings"
@@ -8,32 +9,123 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // SkipRouter 路由请求跳过的path 最后一个/匹配即可
+//
+// Deprecated: 运行期修改全局 map 存在数据竞争，请使用 NewTokenMiddleware。
 var SkipRouter = map[string]bool{}
 
-func Token(c *gin.Context) {
-	path := strings.Split(c.Request.URL.Path, "/")
+// SkipPrefixes 路由请求跳过的完整路径前缀，例如 /v1/auth/oauth/
+//
+// Deprecated: 请使用 NewTokenMiddleware 与 WithSkipPrefixes。
+var SkipPrefixes []string
+
+// CookieName 存放 token 的 httpOnly cookie 名称，请求头缺少 Authorization 时从该 cookie 读取
+//
+// Deprecated: 请使用 NewTokenMiddleware 与 WithCookieName。
+var CookieName string
+
+// Secret token 签名密钥，启动时由配置设置
+//
+// Deprecated: 请使用 NewTokenMiddleware。
+var Secret string
+
+// tokenConfig token 中间件配置，创建后不再修改，可被并发请求安全读取
+type tokenConfig struct {
+	secret       string
+	skipPaths    map[string]bool // 路径最后一段匹配即跳过
+	skipPrefixes []string        // 完整路径前缀匹配即跳过
+	cookieName   string          // 为空时不读取 cookie
+	blacklist    Blacklist       // 为空时不检查 token 是否已吊销
+}
+
+// TokenOption token 中间件可选配置
+type TokenOption func(*tokenConfig)
+
+// WithSkipPrefixes 跳过以指定前缀开头的完整路径，例如 /swagger/
+func WithSkipPrefixes(prefixes ...string) TokenOption {
+	return func(tc *tokenConfig) {
+		tc.skipPrefixes = append(tc.skipPrefixes, prefixes...)
+	}
+}
+
+// WithCookieName 请求头缺少 Authorization 时从指定的 httpOnly cookie 读取 token
+func WithCookieName(name string) TokenOption {
+	return func(tc *tokenConfig) {
+		tc.cookieName = name
+	}
+}
+
+// WithBlacklist 校验通过后检查 token 是否已被吊销（注销）
+func WithBlacklist(blacklist Blacklist) TokenOption {
+	return func(tc *tokenConfig) {
+		tc.blacklist = blacklist
+	}
+}
+
+// NewTokenMiddleware 创建 token 校验中间件
+// skipPaths 为路径最后一段（例如 login），命中时不校验 token；配置在创建时复制，之后不可修改
+func NewTokenMiddleware(skipPaths []string, secret string, opts ...TokenOption) gin.HandlerFunc {
+	tc := &tokenConfig{
+		secret:    secret,
+		skipPaths: make(map[string]bool, len(skipPaths)),
+	}
+	for _, p := range skipPaths {
+		tc.skipPaths[p] = true
+	}
+	for _, opt := range opts {
+		opt(tc)
+	}
+	return tc.handle
+}
+
+// skip 判断请求路径是否跳过 token 校验
+func (tc *tokenConfig) skip(urlPath string) bool {
+	path := strings.Split(urlPath, "/")
+	if tc.skipPaths[path[len(path)-1]] {
+		return true
+	}
+	for _, prefix := range tc.skipPrefixes {
+		if strings.HasPrefix(urlPath, prefix) {
+			return true
+		}
+	}
+	return false
+}
 
-	lastPath := path[len(path)-1]
-	if SkipRouter[lastPath] {
+// handle 校验请求中的 token
+func (tc *tokenConfig) handle(c *gin.Context) {
+	if tc.skip(c.Request.URL.Path) {
 		c.Next()
 		return
 	}
-	header := c.Request.Header.Get("Authorization")
-	if len(header) == 0 {
-		response.Error(c, 403, apperror.TokenMissing)
+	// Parse the header to get the token part.
+	t := strings.Replace(c.Request.Header.Get("Authorization"), "Bearer ", "", 1)
+	if len(t) == 0 && tc.cookieName != "" {
+		// 浏览器客户端将 token 存放在 httpOnly cookie 中
+		t, _ = c.Cookie(tc.cookieName)
+	}
+	if len(t) == 0 {
+		response.Forbidden(c, apperror.TokenMissing)
 		c.Abort()
 		return
 	}
-	// Load the jwt secret from the gin config
-	js, _ := c.Get("jwt-secret")
-	secret := js.(string)
+	tc.parseToken(t, c)
+}
 
-	// Parse the header to get the token part.
-	t := strings.Replace(header, "Bearer ", "", 1)
-	parseToken(t, secret, c)
+// Token 使用全局变量配置的 token 校验中间件
+//
+// Deprecated: 请使用 NewTokenMiddleware。
+func Token(c *gin.Context) {
+	tc := &tokenConfig{
+		secret:       Secret,
+		skipPaths:    SkipRouter,
+		skipPrefixes: SkipPrefixes,
+		cookieName:   CookieName,
+	}
+	tc.handle(c)
 }
 
 // secretFunc validates the secret format.
@@ -47,48 +139,201 @@ func secretFunc(secret string) jwt.Keyfunc {
 	}
 }
 
-func parseToken(tokenString string, secret string, c *gin.Context) {
-	// Parse the token.
-	token, err := jwt.Parse(tokenString, secretFunc(secret))
-
-	// Parse error.
+func (tc *tokenConfig) parseToken(tokenString string, c *gin.Context) {
+	ctx, err := ParseToken(tokenString, tc.secret)
 	if err != nil {
-		response.Error(c, 403, apperror.TokenInvalid)
+		response.Forbidden(c, err.Message)
 		c.Abort()
 		return
 	}
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := int(claims["id"].(float64))
-		username := claims["username"].(string)
-		c.Set("userid", userID)
-		c.Set("username", username)
-		c.Set("token", tokenString)
-		c.Next()
-	} else {
-		// token 过期了
-		response.Error(c, 403, apperror.TokenExpired)
+	if err := CheckRevoked(tc.blacklist, ctx); err != nil {
+		response.Error(c, err.Code, err.Message)
 		c.Abort()
+		return
 	}
+	c.Set("userid", ctx.ID)
+	c.Set("username", ctx.Username)
+	c.Set("role", ctx.Role)
+	c.Set("token", tokenString)
+	c.Set("jti", ctx.JTI)
+	c.Set("token_expires_at", ctx.ExpiresAt)
+	c.Next()
 }
 
+// CheckRevoked 检查 token 是否已吊销，已吊销返回 401；黑名单不可用时返回 503，不放行请求
+// blacklist 为空或 token 不含 jti 时不检查
+func CheckRevoked(blacklist Blacklist, user *Context) *apperror.Error {
+	if blacklist == nil || user.JTI == "" {
+		return nil
+	}
+	revoked, err := blacklist.IsRevoked(user.JTI)
+	if err != nil {
+		return apperror.Wrap(err, 503, apperror.TokenCheckFailed)
+	}
+	if revoked {
+		return apperror.New(401, apperror.TokenRevoked)
+	}
+	return nil
+}
+
+// ParseToken 校验 token 字符串并返回其中的用户信息
+// 供 gin 中间件与 gRPC 拦截器共用
+func ParseToken(tokenString string, secret string) (*Context, *apperror.Error) {
+	// Parse the token.
+	token, err := jwt.Parse(tokenString, secretFunc(secret))
+
+	// Parse error. 区分过期与无效，客户端据此决定刷新 token 还是重新登录
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, apperror.Wrap(err, 403, apperror.TokenExpired)
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return nil, apperror.Wrap(err, 403, apperror.TokenNotValidYet)
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return nil, apperror.Wrap(err, 403, apperror.TokenInvalid)
+	case err != nil:
+		return nil, apperror.Wrap(err, 403, apperror.TokenInvalid)
+	}
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		// refresh token 只能用于 /v1/refresh，不能访问其他接口
+		if claims["typ"] == TokenTypeRefresh {
+			return nil, apperror.New(403, apperror.TokenInvalid)
+		}
+		return contextFromClaims(claims)
+	}
+	// token 过期了
+	return nil, apperror.New(403, apperror.TokenExpired)
+}
+
+// ParseExpiredToken 校验签名但忽略过期时间等时间声明，返回 token 中的用户信息
+// 仅用于刷新 token 时从已过期的 access token 中取出用户
+func ParseExpiredToken(tokenString string, secret string) (*Context, *apperror.Error) {
+	token, err := jwt.Parse(tokenString, secretFunc(secret), jwt.WithoutClaimsValidation())
+	if err != nil {
+		return nil, apperror.Wrap(err, 401, apperror.TokenInvalid)
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] == TokenTypeRefresh {
+		return nil, apperror.New(401, apperror.TokenInvalid)
+	}
+	return contextFromClaims(claims)
+}
+
+// ParseRefreshToken 校验 refresh token 并返回其中的用户 ID
+func ParseRefreshToken(tokenString string, secret string) (uint, *apperror.Error) {
+	token, err := jwt.Parse(tokenString, secretFunc(secret))
+	if err != nil {
+		return 0, apperror.Wrap(err, 401, apperror.RefreshTokenInvalid)
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != TokenTypeRefresh {
+		return 0, apperror.New(401, apperror.RefreshTokenInvalid)
+	}
+	id, ok := claims["id"].(float64)
+	if !ok {
+		return 0, apperror.New(401, apperror.RefreshTokenInvalid)
+	}
+	return uint(id), nil
+}
+
+// contextFromClaims 从声明中取出用户信息，字段缺失或类型错误时视为无效 token
+func contextFromClaims(claims jwt.MapClaims) (*Context, *apperror.Error) {
+	id, ok := claims["id"].(float64)
+	if !ok {
+		return nil, apperror.New(403, apperror.TokenInvalid)
+	}
+	username, ok := claims["username"].(string)
+	if !ok {
+		return nil, apperror.New(403, apperror.TokenInvalid)
+	}
+	// 旧版本签发的 token 不含 role，视为没有任何角色
+	role, _ := claims["role"].(string)
+	jti, _ := claims["jti"].(string)
+	user := &Context{ID: uint(id), Username: username, Role: role, JTI: jti}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		user.ExpiresAt = exp.Time
+	}
+	return user, nil
+}
+
+// Context token 中解析出的用户信息
 type Context struct {
-	ID       int
-	Username string
-}
-
-// Sign 生成一个JWT token并返回token字符串
-// 根据提供的上下文、用户信息、密钥和持续时间创建签名的JWT token
-func Sign(c Context, secret string, duration time.Duration) (tokenString string, err error) {
-	// 创建包含用户信息和时间戳的JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":       c.ID,
-		"username": c.Username,
-		"nbf":      time.Now().Unix(),
-		"iat":      time.Now().Unix(),
-		"exp":      time.Now().Add(duration).Unix(),
-	})
+	ID        uint
+	Username  string
+	Role      string
+	JTI       string    // token 唯一标识，用于吊销
+	ExpiresAt time.Time // token 过期时间，吊销记录保留到该时间
+}
+
+// TokenInfo 签发的 token 及其元数据
+type TokenInfo struct {
+	Token     string    // token 字符串
+	ExpiresAt time.Time // 过期时间
+	IssuedAt  time.Time // 签发时间
+	JTI       string    // token 唯一标识，用于吊销
+}
+
+// MapClaims token 声明，由调用方构建
+type MapClaims = jwt.MapClaims
+
+// Sign 使用调用方构建的声明生成JWT token并返回token及其元数据
+// nbf、iat、exp 未设置时自动补充；jti 未设置时生成 UUID，便于吊销
+func Sign(claims MapClaims, secret string, duration time.Duration) (TokenInfo, error) {
+	now := time.Now()
+
+	// 复制一份，避免修改调用方的 map
+	mc := make(MapClaims, len(claims)+4)
+	for k, v := range claims {
+		mc[k] = v
+	}
+	if _, ok := mc["nbf"]; !ok {
+		mc["nbf"] = now.Unix()
+	}
+	if _, ok := mc["iat"]; !ok {
+		mc["iat"] = now.Unix()
+	}
+	if _, ok := mc["exp"]; !ok {
+		mc["exp"] = now.Add(duration).Unix()
+	}
+	if _, ok := mc["jti"]; !ok {
+		mc["jti"] = uuid.NewString()
+	}
+
+	info := TokenInfo{
+		IssuedAt:  unixClaim(mc["iat"], now),
+		ExpiresAt: unixClaim(mc["exp"], now.Add(duration)),
+	}
+	info.JTI, _ = mc["jti"].(string)
+
 	// 使用指定的密钥对token进行签名
-	tokenString, err = token.SignedString([]byte(secret))
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, mc).SignedString([]byte(secret))
+	if err != nil {
+		return TokenInfo{}, err
+	}
 
-	return
+	info.Token = tokenString
+	return info, nil
+}
+
+// TokenTypeRefresh refresh token 的 typ 声明值
+const TokenTypeRefresh = "refresh"
+
+// SignRefresh 签发 refresh token，只包含用户 ID 与 typ=refresh，不能用于访问接口
+func SignRefresh(userID uint, secret string, duration time.Duration) (TokenInfo, error) {
+	return Sign(MapClaims{"id": userID, "typ": TokenTypeRefresh}, secret, duration)
+}
+
+// unixClaim 将 Unix 时间戳声明转换为 time.Time，类型不支持时返回 fallback
+func unixClaim(v any, fallback time.Time) time.Time {
+	switch t := v.(type) {
+	case int64:
+		return time.Unix(t, 0)
+	case int:
+		return time.Unix(int64(t), 0)
+	case float64:
+		return time.Unix(int64(t), 0)
+	case time.Time:
+		return t
+	default:
+		return fallback
+	}
 }
@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RequestIDKey 请求 ID 在 gin 上下文与日志字段中使用的键
+const RequestIDKey = "request_id"
+
+// loggerKey context 中保存 logger 的键类型
+type loggerKey struct{}
+
+// WithLogger 将 logger 保存到 context 中，通常由中间件附加请求相关字段后写入
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext 获取 context 中的 logger，未设置时返回默认 logger
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
@@ -0,0 +1,83 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gojet/models"
+
+	"github.com/nats-io/nats.go"
+)
+
+// 用户生命周期事件主题
+const (
+	SubjectUserCreated = "user.created"
+	SubjectUserUpdated = "user.updated"
+	SubjectUserDeleted = "user.deleted"
+)
+
+// UserEvent 用户事件内容，不包含密码哈希等敏感字段
+// 状态变更和删除事件只携带 ID 与变化的字段，未设置的字段不输出
+type UserEvent struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username,omitempty"`
+	NickName string `json:"nick_name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+// NewUserEvent 根据用户生成事件内容
+func NewUserEvent(user *models.User) UserEvent {
+	return UserEvent{
+		ID:       user.ID,
+		Username: user.Username,
+		NickName: user.NickName,
+		Email:    user.Email,
+		Role:     user.Role,
+		Status:   user.Status,
+	}
+}
+
+// Publisher 消息发布接口 - 将事件序列化为 JSON 后发布到指定主题
+type Publisher interface {
+	Publish(subject string, payload any) error
+	Close() error
+}
+
+// NATSPublisher 基于 NATS 的消息发布实现
+type NATSPublisher struct {
+	conn *nats.Conn
+}
+
+// NewNATSPublisher 连接 NATS 服务器并创建发布者
+func NewNATSPublisher(url string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("连接 NATS 失败: %w", err)
+	}
+	return &NATSPublisher{conn: conn}, nil
+}
+
+// Publish 发布消息
+func (p *NATSPublisher) Publish(subject string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %w", err)
+	}
+	return p.conn.Publish(subject, data)
+}
+
+// Close 刷新缓冲区并关闭连接
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
+
+// NoopPublisher 空实现 - 未配置消息服务器或测试时使用
+type NoopPublisher struct{}
+
+// Publish 丢弃消息
+func (NoopPublisher) Publish(string, any) error { return nil }
+
+// Close 无需释放资源
+func (NoopPublisher) Close() error { return nil }
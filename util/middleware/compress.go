@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipPool 复用 gzip.Writer，避免每个请求重新分配压缩缓冲区
+var gzipPool = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// compressedTypes 本身已经压缩过的内容类型前缀，再次压缩只会浪费 CPU
+var compressedTypes = []string{
+	"image/", "video/", "audio/",
+	"application/zip", "application/gzip", "application/x-gzip", "application/x-7z-compressed",
+}
+
+// Compress 客户端支持 gzip 且响应体达到 minBytes 字节时压缩响应
+// 响应体先在内存中缓冲，达到阈值后才开始压缩，小响应保持原样输出
+func Compress(minBytes int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		w := &gzipWriter{ResponseWriter: c.Writer, minBytes: minBytes}
+		c.Writer = w
+		defer w.finish()
+
+		// 无论是否压缩，响应内容都与 Accept-Encoding 有关，缓存需要区分
+		c.Header("Vary", "Accept-Encoding")
+		c.Next()
+	}
+}
+
+// acceptsGzip 判断 Accept-Encoding 是否接受 gzip（q=0 表示明确拒绝）
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		q := strings.ReplaceAll(params, " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
+
+// gzipWriter 缓冲响应体，达到阈值后切换为 gzip 输出
+type gzipWriter struct {
+	gin.ResponseWriter
+	minBytes int
+	buf      []byte
+	gz       *gzip.Writer
+	decided  bool // 是否已确定压缩或不压缩
+}
+
+// Write 写入响应体
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) < w.minBytes {
+		return len(data), nil
+	}
+	if err := w.decide(w.compressible()); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// WriteString 写入字符串响应体
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 流式响应需要立即输出，尚未达到阈值时不再压缩
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// compressible 判断响应是否适合压缩
+func (w *gzipWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, prefix := range compressedTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// decide 确定是否压缩，并输出已缓冲的内容
+func (w *gzipWriter) decide(compress bool) error {
+	w.decided = true
+	buf := w.buf
+	w.buf = nil
+
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		// 压缩后长度未知，由 net/http 使用分块传输
+		header.Del("Content-Length")
+
+		w.gz = gzipPool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+		_, err := w.gz.Write(buf)
+		return err
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// finish 请求结束时输出未达到阈值的内容或结束 gzip 流
+func (w *gzipWriter) finish() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+		w.gz.Reset(nil)
+		gzipPool.Put(w.gz)
+		w.gz = nil
+	}
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gojet/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newCompressEngine 创建启用压缩的引擎，GET / 以 contentType 返回 body
+func newCompressEngine(minBytes int, contentType string, body []byte) *gin.Engine {
+	r := gin.New()
+	r.Use(Compress(minBytes))
+	r.GET("/", func(c *gin.Context) {
+		c.Data(http.StatusOK, contentType, body)
+	})
+	return r
+}
+
+func TestCompress(t *testing.T) {
+	large := []byte(strings.Repeat(`{"username":"alice"},`, 100))
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           []byte
+		wantGzip       bool
+	}{
+		{"达到阈值", "gzip, deflate", "application/json", large, true},
+		{"未达到阈值", "gzip", "application/json", []byte(`{}`), false},
+		{"客户端不支持", "", "application/json", large, false},
+		{"客户端拒绝 gzip", "gzip;q=0", "application/json", large, false},
+		{"已压缩的内容类型", "gzip", "image/png", large, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			newCompressEngine(256, tt.contentType, tt.body).ServeHTTP(w, req)
+
+			gzipped := w.Header().Get("Content-Encoding") == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Fatalf("Content-Encoding = %q, want gzip = %v", w.Header().Get("Content-Encoding"), tt.wantGzip)
+			}
+			var body io.Reader = w.Body
+			if gzipped {
+				if w.Header().Get("Content-Length") != "" {
+					t.Errorf("Content-Length = %q, want empty", w.Header().Get("Content-Length"))
+				}
+				gr, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = gr
+			}
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(tt.body) {
+				t.Errorf("body length = %d, want %d", len(got), len(tt.body))
+			}
+		})
+	}
+}
+
+// benchmarkUsers 生成 n 个用户的 JSON 列表响应
+func benchmarkUsers(n int) []*models.User {
+	users := make([]*models.User, n)
+	for i := range users {
+		users[i] = &models.User{
+			ID:       uint(i + 1),
+			Username: fmt.Sprintf("user%d", i),
+			NickName: fmt.Sprintf("User %d", i),
+			Email:    fmt.Sprintf("user%d@example.com", i),
+			Role:     models.RoleUser,
+			Status:   models.StatusActive,
+		}
+	}
+	return users
+}
+
+// BenchmarkCompress 对比 1000 个用户的列表响应压缩与不压缩的吞吐量，wire-bytes/op 为实际输出的字节数
+func BenchmarkCompress(b *testing.B) {
+	users := benchmarkUsers(1000)
+
+	for _, bm := range []struct {
+		name           string
+		acceptEncoding string
+	}{
+		{"不压缩", ""},
+		{"gzip", "gzip"},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			r := gin.New()
+			r.Use(Compress(1024))
+			r.GET("/v1/users", func(c *gin.Context) {
+				c.JSON(http.StatusOK, users)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+			if bm.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", bm.acceptEncoding)
+			}
+
+			var wireBytes int
+			b.ReportAllocs()
+			for b.Loop() {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				wireBytes = w.Body.Len()
+			}
+			b.ReportMetric(float64(wireBytes), "wire-bytes/op")
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gojet/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS 跨域资源共享中间件，并直接响应预检（OPTIONS）请求
+// 需注册在 token 中间件之前，预检请求不携带 Authorization 头
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	wildcard := slices.Contains(cfg.AllowedOrigins, "*")
+	if wildcard && cfg.AllowCredentials {
+		// 规范不允许 "*" 与凭证同时使用，浏览器会拒绝携带 cookie 的跨域请求
+		slog.Warn("CORS 允许任意来源时 allow_credentials 不生效，需要携带凭证时请列出具体来源")
+	}
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(cfg.MaxAge)
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			// 非跨域请求
+			c.Next()
+			return
+		}
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !wildcard && !slices.Contains(cfg.AllowedOrigins, origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			// 不返回 CORS 头，由浏览器拦截响应
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		if wildcard {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Add("Vary", "Origin")
+			if cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+		h.Set("Access-Control-Expose-Headers", "X-Request-ID, Retry-After")
+
+		if preflight {
+			h.Set("Access-Control-Allow-Methods", methods)
+			h.Set("Access-Control-Allow-Headers", headers)
+			if cfg.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP 请求总数",
+	}, []string{"method", "path", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP 请求处理耗时（秒）",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path"})
+)
+
+func init() {
+	prometheus.MustRegister(httpRequestsTotal, httpRequestDuration)
+}
+
+// Prometheus 记录请求数与处理耗时，指标注册在 Prometheus 默认注册表中
+// path 使用路由模板（例如 /v1/users/:id），避免路径参数导致指标数量无限增长
+func Prometheus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			// 未匹配任何路由
+			path = "unmatched"
+		}
+		httpRequestsTotal.WithLabelValues(c.Request.Method, path, strconv.Itoa(c.Writer.Status())).Inc()
+		httpRequestDuration.WithLabelValues(c.Request.Method, path).Observe(time.Since(start).Seconds())
+	}
+}
+
+// RegisterDBStats 在 Prometheus 默认注册表中注册数据库连接池指标，抓取时实时读取 sql.DBStats
+func RegisterDBStats(db *sql.DB) error {
+	gauges := []struct {
+		name, help string
+		value      func(sql.DBStats) int
+	}{
+		{"db_open_connections", "数据库当前打开的连接数", func(s sql.DBStats) int { return s.OpenConnections }},
+		{"db_in_use", "数据库正在使用的连接数", func(s sql.DBStats) int { return s.InUse }},
+		{"db_idle", "数据库空闲连接数", func(s sql.DBStats) int { return s.Idle }},
+	}
+	for _, g := range gauges {
+		value := g.value
+		collector := prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: g.name, Help: g.help}, func() float64 {
+			return float64(value(db.Stats()))
+		})
+		if err := prometheus.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"gojet/util/apperror"
+	"gojet/util/response"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// visitorTTL 客户端超过该时间没有请求时清理其限流器，避免内存无限增长
+const visitorTTL = 3 * time.Minute
+
+// RateLimit 全局限流，所有请求共享一个令牌桶，每秒补充 rps 个令牌，最多积累 burst 个
+func RateLimit(rps int, burst int) gin.HandlerFunc {
+	limiter := rate.NewLimiter(rate.Limit(rps), burst)
+	return func(c *gin.Context) {
+		allow(c, limiter)
+	}
+}
+
+// visitor 单个客户端 IP 的限流器
+type visitor struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// ipLimiter 按客户端 IP 分别限流
+type ipLimiter struct {
+	mu        sync.Mutex
+	visitors  map[string]*visitor
+	lastSweep time.Time
+	rps       rate.Limit
+	burst     int
+}
+
+// RateLimitByIP 按客户端 IP 限流，每个 IP 独立的令牌桶
+// 客户端 IP 由 c.ClientIP 获取，位于代理之后时需配置 app.trusted_proxies
+func RateLimitByIP(rps int, burst int) gin.HandlerFunc {
+	l := &ipLimiter{
+		visitors:  make(map[string]*visitor),
+		lastSweep: time.Now(),
+		rps:       rate.Limit(rps),
+		burst:     burst,
+	}
+	return func(c *gin.Context) {
+		allow(c, l.get(c.ClientIP()))
+	}
+}
+
+// get 获取客户端 IP 对应的限流器，不存在时创建；顺带清理长时间未访问的客户端
+func (l *ipLimiter) get(ip string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > visitorTTL {
+		for key, v := range l.visitors {
+			if now.Sub(v.lastSeen) > visitorTTL {
+				delete(l.visitors, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	v, ok := l.visitors[ip]
+	if !ok {
+		v = &visitor{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.visitors[ip] = v
+	}
+	v.lastSeen = now
+	return v.limiter
+}
+
+// allow 从令牌桶取出一个令牌，令牌不足时返回 429 并通过 Retry-After 告知需要等待的时间
+func allow(c *gin.Context, limiter *rate.Limiter) {
+	r := limiter.Reserve()
+	if !r.OK() {
+		// burst 为 0 时永远无法获得令牌
+		response.TooManyRequests(c, apperror.TooManyRequests, time.Second)
+		c.Abort()
+		return
+	}
+	if delay := r.Delay(); delay > 0 {
+		// 不等待令牌，归还预占的令牌，避免被拒绝的请求继续消耗配额
+		r.Cancel()
+		response.TooManyRequests(c, apperror.TooManyRequests, delay)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newLimitedEngine 创建只有一个路由的引擎，所有请求经过 limiter
+func newLimitedEngine(limiter gin.HandlerFunc) *gin.Engine {
+	r := gin.New()
+	r.Use(limiter)
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+// get 以指定客户端 IP 发送请求
+func get(r http.Handler, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = ip + ":12345"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// checkRetryAfter 检查 Retry-After 为正整数秒
+func checkRetryAfter(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+	seconds, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || seconds < 1 {
+		t.Errorf("Retry-After = %q, want positive seconds", w.Header().Get("Retry-After"))
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	const burst = 3
+	// 每秒只补充 1 个令牌，测试期间不会补满
+	r := newLimitedEngine(RateLimit(1, burst))
+
+	for i := range burst {
+		if w := get(r, "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, w.Code)
+		}
+	}
+	// 全局限流不区分客户端
+	w := get(r, "10.0.0.2")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request %d: status = %d, want 429", burst+1, w.Code)
+	}
+	checkRetryAfter(t, w)
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	const burst = 2
+	r := newLimitedEngine(RateLimitByIP(1, burst))
+
+	for i := range burst {
+		if w := get(r, "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, w.Code)
+		}
+	}
+	w := get(r, "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request %d: status = %d, want 429", burst+1, w.Code)
+	}
+	checkRetryAfter(t, w)
+
+	// 其他客户端拥有独立的令牌桶
+	if w := get(r, "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("other IP: status = %d, want 200", w.Code)
+	}
+}
+
+func TestRateLimitZeroBurst(t *testing.T) {
+	w := get(newLimitedEngine(RateLimit(10, 0)), "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1", got)
+	}
+}
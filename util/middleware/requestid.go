@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"gojet/util/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader 请求 ID 请求头/响应头名称
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID 为每个请求分配请求 ID（优先沿用上游传入的 X-Request-ID），
+// 保存到 gin 上下文并写入响应头，同时将带有 request_id 字段的默认 logger 放入请求 context
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = uuid.NewString()
+		}
+		c.Set(logging.RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+
+		ctx := logging.WithLogger(c.Request.Context(), slog.Default().With(logging.RequestIDKey, id))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"slices"
+
+	"gojet/util/apperror"
+	"gojet/util/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole 要求当前用户具有指定角色之一，否则返回 403
+// 依赖 token 中间件写入的 role，需注册在 token 中间件之后
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(roles, c.GetString("role")) {
+			response.Error(c, 403, apperror.PermissionDenied)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
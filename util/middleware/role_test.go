@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name  string
+		role  string
+		roles []string
+		want  int
+	}{
+		{"管理员", "admin", []string{"admin"}, http.StatusOK},
+		{"普通用户", "user", []string{"admin"}, http.StatusForbidden},
+		{"允许多个角色", "user", []string{"admin", "user"}, http.StatusOK},
+		// token 中没有 role 声明时按无权限处理
+		{"缺少角色", "", []string{"admin"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			r := gin.New()
+			r.GET("/", func(c *gin.Context) {
+				if tt.role != "" {
+					c.Set("role", tt.role)
+				}
+				c.Next()
+			}, RequireRole(tt.roles...), func(c *gin.Context) {
+				called = true
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if called != (tt.want == http.StatusOK) {
+				t.Errorf("handler called = %v", called)
+			}
+		})
+	}
+}
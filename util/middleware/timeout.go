@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"gojet/util/logging"
+	"gojet/util/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// ErrHandlerTimeout 请求超时后处理函数继续写入响应时返回
+var ErrHandlerTimeout = errors.New("请求已超时，响应已返回")
+
+// Timeout 为每个请求设置截止时间，超时后立即返回 503
+//
+// 后续中间件和处理函数在独立的 goroutine 中执行，写入的响应先缓冲，正常结束时再一次性输出；
+// 超时后缓冲内容被丢弃，处理函数继续写入会得到 ErrHandlerTimeout。
+// gin.Context 会在请求结束后被复用，因此返回 503 之后仍会等待处理函数退出，
+// 数据库查询等操作使用请求 context，超时取消后会很快返回。
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		w := &timeoutWriter{
+			ResponseWriter: c.Writer,
+			header:         c.Writer.Header().Clone(),
+			status:         http.StatusOK,
+			size:           -1,
+		}
+		c.Writer = w
+		c.Request = c.Request.WithContext(ctx)
+
+		done := make(chan any, 1)
+		go func() {
+			defer func() {
+				// 处理函数中的 panic 需要转交给当前 goroutine，由 gin.Recovery 处理
+				done <- recover()
+			}()
+			c.Next()
+		}()
+
+		var p any
+		select {
+		case p = <-done:
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// 客户端断开连接导致的取消不是超时，等待处理函数按正常流程结束
+				p = <-done
+				break
+			}
+			w.timeout(c.GetString(logging.RequestIDKey))
+			if p := <-done; p != nil {
+				slog.Error("请求超时后处理函数发生 panic", "panic", p, "path", c.Request.URL.Path)
+			}
+			c.Writer = w.ResponseWriter
+			c.Abort()
+			return
+		}
+
+		c.Writer = w.ResponseWriter
+		if p != nil {
+			panic(p)
+		}
+		w.commit()
+	}
+}
+
+// timeoutWriter 缓冲处理函数写入的响应头和响应体，超时后丢弃
+type timeoutWriter struct {
+	gin.ResponseWriter
+
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	status   int
+	size     int
+	timedOut bool
+}
+
+// Header 返回缓冲的响应头，处理函数不会直接修改真实响应头
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader 记录状态码
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size < 0 {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow 标记响应头已写入
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size < 0 {
+		w.size = 0
+	}
+}
+
+// Write 写入响应体缓冲
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, ErrHandlerTimeout
+	}
+	if w.size < 0 {
+		w.size = 0
+	}
+	w.size += len(data)
+	return w.buf.Write(data)
+}
+
+// WriteString 写入字符串响应体
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Status 返回处理函数设置的状态码
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+// Size 返回已写入的响应体字节数，未写入时为 -1
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+// Written 是否已写入响应
+func (w *timeoutWriter) Written() bool {
+	return w.Size() >= 0
+}
+
+// Flush 响应在请求结束时统一输出，中途刷新不生效
+func (w *timeoutWriter) Flush() {}
+
+// commit 处理函数正常结束，输出缓冲的响应
+func (w *timeoutWriter) commit() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	dst := w.ResponseWriter.Header()
+	clear(dst)
+	maps.Copy(dst, w.header)
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.size < 0 {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+	if w.buf.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+}
+
+// timeout 丢弃缓冲内容并直接输出 503 响应
+func (w *timeoutWriter) timeout(requestID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.timedOut = true
+	w.buf.Reset()
+	w.ResponseWriter.WriteHeader(http.StatusServiceUnavailable)
+	_ = render.JSON{Data: response.Response{
+		Code:      http.StatusServiceUnavailable,
+		Message:   "请求超时",
+		RequestID: requestID,
+	}}.Render(w.ResponseWriter)
+	// 处理函数退出前不会返回，先将 503 响应发送给客户端
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gojet/util/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testTimeout 测试使用的请求超时时间
+const testTimeout = 50 * time.Millisecond
+
+// newTimeoutEngine 创建启用超时中间件的引擎，panic 由 recovery 记录后返回 500
+func newTimeoutEngine(handler gin.HandlerFunc, recovered *any) *gin.Engine {
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, err any) {
+		*recovered = err
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.Use(Timeout(testTimeout))
+	r.GET("/", handler)
+	return r
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     func(c *gin.Context, writeErr chan<- error)
+		want        int
+		wantMessage string // 为空时不检查响应体
+		wantPanic   any
+		wantErr     error // 处理函数写入响应时得到的错误
+	}{
+		{
+			name: "处理函数及时返回",
+			handler: func(c *gin.Context, writeErr chan<- error) {
+				c.Header("X-Handler", "done")
+				response.Success(c, "fast", nil)
+			},
+			want:        http.StatusOK,
+			wantMessage: "fast",
+		},
+		{
+			name: "处理函数响应 context 取消",
+			handler: func(c *gin.Context, writeErr chan<- error) {
+				<-c.Request.Context().Done()
+				response.InternalServerError(c, c.Request.Context().Err().Error())
+			},
+			want:        http.StatusServiceUnavailable,
+			wantMessage: "请求超时",
+		},
+		{
+			name: "超时后继续写入",
+			handler: func(c *gin.Context, writeErr chan<- error) {
+				time.Sleep(2 * testTimeout)
+				_, err := c.Writer.Write([]byte("late"))
+				writeErr <- err
+			},
+			want:        http.StatusServiceUnavailable,
+			wantMessage: "请求超时",
+			wantErr:     ErrHandlerTimeout,
+		},
+		{
+			name: "panic 交给 recovery",
+			handler: func(c *gin.Context, writeErr chan<- error) {
+				panic("boom")
+			},
+			want:      http.StatusInternalServerError,
+			wantPanic: "boom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeErr := make(chan error, 1)
+			var recovered any
+			r := newTimeoutEngine(func(c *gin.Context) { tt.handler(c, writeErr) }, &recovered)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.wantMessage != "" {
+				var resp response.Response
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("body is not a single JSON response: %v, body: %s", err, w.Body.String())
+				}
+				if resp.Message != tt.wantMessage {
+					t.Errorf("message = %q, want %q", resp.Message, tt.wantMessage)
+				}
+			}
+			if recovered != tt.wantPanic {
+				t.Errorf("recovered = %v, want %v", recovered, tt.wantPanic)
+			}
+			if tt.wantErr != nil {
+				if err := <-writeErr; !errors.Is(err, tt.wantErr) {
+					t.Errorf("write after timeout err = %v, want %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+// flushRecorder 在 Flush 时通知测试，用于确认 503 在处理函数退出前已发送
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed chan int
+}
+
+// Flush 记录刷新时的状态码
+func (w *flushRecorder) Flush() {
+	w.ResponseRecorder.Flush()
+	select {
+	case w.flushed <- w.Code:
+	default:
+	}
+}
+
+func TestTimeoutFlushesBeforeHandlerReturns(t *testing.T) {
+	release := make(chan struct{})
+	var recovered any
+	r := newTimeoutEngine(func(c *gin.Context) {
+		// 忽略 context 取消，直到测试确认收到 503 才退出
+		<-release
+	}, &recovered)
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan int, 1)}
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	select {
+	case code := <-w.flushed:
+		if code != http.StatusServiceUnavailable {
+			t.Errorf("flushed status = %d, want 503", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("503 not flushed while handler is still running")
+	}
+
+	select {
+	case <-served:
+		t.Fatal("middleware returned before the handler exited")
+	default:
+	}
+	close(release)
+	<-served
+}
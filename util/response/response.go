@@ -2,12 +2,16 @@ package response
 
 import (
 	"errors"
-	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"gojet/util/apperror"
+	"gojet/util/logging"
 )
 
 // Response 统一响应结构体
@@ -15,40 +19,58 @@ type Response struct {
 	Code    int    `json:"code"`    // 状态码
 	Message string `json:"message"` // 消息
 	Data    any    `json:"data"`    // 数据
+
+	RequestID string `json:"request_id,omitempty"` // 请求 ID，与响应头 X-Request-ID 一致，便于根据客户端报错查找服务端日志
+}
+
+// PagedResponse 分页数据，作为 Response.Data 返回
+type PagedResponse[T any] struct {
+	Total int64 `json:"total"` // 总记录数
+	Page  int   `json:"page"`  // 当前页码，从 1 开始
+	Size  int   `json:"size"`  // 每页条数
+	Data  []T   `json:"data"`  // 当前页数据
 }
 
-// Success 返回成功响应
-func Success(c *gin.Context, message string, data any) {
+// Success 返回成功响应，status 可选，默认 200
+func Success(c *gin.Context, message string, data any, status ...int) {
+	code := http.StatusOK
+	if len(status) > 0 {
+		code = status[0]
+	}
 	if message == "" {
 		message = "操作成功"
 	}
-	c.JSON(http.StatusOK, Response{
-		Code:    200,
-		Message: message,
-		Data:    data,
+	c.JSON(code, Response{
+		Code:      code,
+		Message:   message,
+		Data:      data,
+		RequestID: c.GetString(logging.RequestIDKey),
 	})
 }
 
-// Error 返回错误响应
+// Created 返回201创建成功响应
+func Created(c *gin.Context, message string, data any) {
+	Success(c, message, data, http.StatusCreated)
+}
+
+// NoContent 返回204无内容响应
+func NoContent(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+
+// Error 返回错误响应，code 同时作为 HTTP 状态码
 func Error(c *gin.Context, code int, message string) {
-	httpCode := http.StatusBadRequest
-	switch code {
-	case 400:
-		httpCode = http.StatusBadRequest
-	case 401:
-		httpCode = http.StatusUnauthorized
-	case 403:
-		httpCode = http.StatusForbidden
-	case 404:
-		httpCode = http.StatusNotFound
-	case 500:
+	httpCode := code
+	if httpCode < 400 || httpCode > 599 {
+		// 非法的错误码统一按服务器内部错误处理
 		httpCode = http.StatusInternalServerError
 	}
 
 	c.JSON(httpCode, Response{
-		Code:    code,
-		Message: message,
-		Data:    nil,
+		Code:      code,
+		Message:   message,
+		Data:      nil,
+		RequestID: c.GetString(logging.RequestIDKey),
 	})
 }
 
@@ -57,16 +79,106 @@ func BadRequest(c *gin.Context, message string) {
 	Error(c, 400, message)
 }
 
+// Unauthorized 返回401错误
+func Unauthorized(c *gin.Context, message string) {
+	Error(c, 401, message)
+}
+
+// Forbidden 返回403错误
+func Forbidden(c *gin.Context, message string) {
+	Error(c, 403, message)
+}
+
 // NotFound 返回404错误
 func NotFound(c *gin.Context, message string) {
 	Error(c, 404, message)
 }
 
+// Conflict 返回409错误
+func Conflict(c *gin.Context, message string) {
+	Error(c, 409, message)
+}
+
+// UnprocessableEntity 返回422错误，请求格式正确但内容未通过业务校验
+func UnprocessableEntity(c *gin.Context, message string) {
+	Error(c, 422, message)
+}
+
+// ValidationFailed 返回422错误，data 中携带各字段的校验错误
+func ValidationFailed(c *gin.Context, fields any) {
+	c.JSON(http.StatusUnprocessableEntity, Response{
+		Code:      http.StatusUnprocessableEntity,
+		Message:   apperror.ValidationFailed,
+		Data:      fields,
+		RequestID: c.GetString(logging.RequestIDKey),
+	})
+}
+
+// PayloadTooLarge 返回413错误
+func PayloadTooLarge(c *gin.Context, message string) {
+	Error(c, 413, message)
+}
+
+// TooManyRequests 返回429错误，并通过 Retry-After 头告知客户端需要等待的秒数（向上取整，至少 1 秒）
+func TooManyRequests(c *gin.Context, message string, retryAfter time.Duration) {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.FormatInt(max(seconds, 1), 10))
+	Error(c, 429, message)
+}
+
 // InternalServerError 返回500错误
 func InternalServerError(c *gin.Context, message string) {
 	Error(c, 500, message)
 }
 
+// SetCacheHeaders 设置 ETag、Last-Modified 缓存响应头，并处理条件请求
+// 资源未修改时写入 304 并返回 true，调用方应直接返回
+func SetCacheHeaders(c *gin.Context, lastModified time.Time, etag string) bool {
+	if etag != "" {
+		c.Header("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, lastModified, etag) {
+		c.Status(http.StatusNotModified)
+		c.Abort()
+		return true
+	}
+	return false
+}
+
+// notModified 判断条件请求的资源是否未修改
+// 按 RFC 7232 规定，存在 If-None-Match 时忽略 If-Modified-Since
+func notModified(r *http.Request, lastModified time.Time, etag string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etag != "" && etagMatch(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	// HTTP 日期精度为秒，比较前截断
+	return !lastModified.Truncate(time.Second).After(t)
+}
+
+// etagMatch 判断 If-None-Match 请求头是否包含指定 ETag（支持多个值与 *）
+func etagMatch(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // HandleError 统一处理 service 层返回的错误。
 // - 如果是 *errpkg.Error，则按照其中的 Code/Message 返回对应响应。
 // - 否则返回通用 500（服务器内部错误）。
@@ -74,28 +186,39 @@ func HandleError(c *gin.Context, err error) {
 	if err == nil {
 		return
 	}
+	// 使用请求上下文中的 logger，便于与触发错误的请求关联
+	logger := logging.LoggerFromContext(c.Request.Context())
+
 	var e *apperror.Error
 	if errors.As(err, &e) {
 		// 记录错误日志，包含原始错误信息（如果有）
 		if e.Err != nil {
-			slog.Error("应用错误", "code", e.Code, "message", e.Message, "original_error", e.Err)
+			logger.Error("应用错误", "code", e.Code, "message", e.Message, "original_error", e.Err)
 		} else {
-			slog.Error("应用错误", "code", e.Code, "message", e.Message)
+			logger.Error("应用错误", "code", e.Code, "message", e.Message)
 		}
 
 		switch e.Code {
 		case 400:
 			BadRequest(c, e.Message)
+		case 401:
+			Unauthorized(c, e.Message)
+		case 403:
+			Forbidden(c, e.Message)
 		case 404:
 			NotFound(c, e.Message)
+		case 409:
+			Conflict(c, e.Message)
+		case 422:
+			UnprocessableEntity(c, e.Message)
 		case 500:
 			InternalServerError(c, e.Message)
 		default:
-			InternalServerError(c, e.Message)
+			Error(c, e.Code, e.Message)
 		}
 		return
 	}
 	// 非 Error 类型，记录日志并返回通用内部错误
-	slog.Error("未处理的应用错误", "error", err)
+	logger.Error("未处理的应用错误", "error", err)
 	InternalServerError(c, apperror.InternalError)
 }
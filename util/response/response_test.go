@@ -0,0 +1,134 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gojet/util/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestSetCacheHeaders(t *testing.T) {
+	lastModified := time.Date(2024, 5, 1, 8, 30, 15, 500, time.UTC)
+	const etag = `"v1"`
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"无条件请求", nil, http.StatusOK},
+		{"未修改", map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"之后的时间", map[string]string{"If-Modified-Since": lastModified.Add(time.Hour).Format(http.TimeFormat)}, http.StatusNotModified},
+		{"已修改", map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)}, http.StatusOK},
+		{"日期格式错误", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+		{"ETag 匹配", map[string]string{"If-None-Match": `W/"v1"`}, http.StatusNotModified},
+		// 存在 If-None-Match 时忽略 If-Modified-Since
+		{"ETag 不匹配", map[string]string{"If-None-Match": `"v0"`, "If-Modified-Since": lastModified.Format(http.TimeFormat)}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/v1/users/1", nil)
+			for k, v := range tt.headers {
+				c.Request.Header.Set(k, v)
+			}
+
+			if !SetCacheHeaders(c, lastModified, etag) {
+				c.Status(http.StatusOK)
+			}
+			c.Writer.WriteHeaderNow()
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if got := w.Header().Get("Last-Modified"); got != "Wed, 01 May 2024 08:30:15 GMT" {
+				t.Errorf("Last-Modified = %q", got)
+			}
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+		})
+	}
+}
+
+// record 调用 fn 写入响应，返回状态码与解析后的响应体
+func record(t *testing.T, fn func(c *gin.Context)) (int, Response) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	fn(c)
+
+	var resp Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v, body: %s", err, w.Body.String())
+	}
+	return w.Code, resp
+}
+
+func TestErrorHelpers(t *testing.T) {
+	tests := []struct {
+		name   string
+		helper func(c *gin.Context, message string)
+		want   int
+	}{
+		{"BadRequest", BadRequest, http.StatusBadRequest},
+		{"Unauthorized", Unauthorized, http.StatusUnauthorized},
+		{"Forbidden", Forbidden, http.StatusForbidden},
+		{"NotFound", NotFound, http.StatusNotFound},
+		{"Conflict", Conflict, http.StatusConflict},
+		{"UnprocessableEntity", UnprocessableEntity, http.StatusUnprocessableEntity},
+		{"InternalServerError", InternalServerError, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := record(t, func(c *gin.Context) { tt.helper(c, "msg") })
+			if status != tt.want || resp.Code != tt.want || resp.Message != "msg" {
+				t.Errorf("status = %d, body = {code: %d, message: %q}, want %d msg", status, resp.Code, resp.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    int
+		wantMessage string
+	}{
+		{"400", apperror.New(400, apperror.InvalidParams), 400, 400, apperror.InvalidParams},
+		{"404", apperror.New(404, apperror.RecordNotFound), 404, 404, apperror.RecordNotFound},
+		{"409", apperror.New(409, apperror.UserExists), 409, 409, apperror.UserExists},
+		{"422", apperror.New(422, apperror.ValidationFailed), 422, 422, apperror.ValidationFailed},
+		{"500", apperror.Wrap(errors.New("connection reset"), 500, apperror.DBQueryError), 500, 500, apperror.DBQueryError},
+		{"其他错误码", apperror.New(503, "服务暂不可用"), 503, 503, "服务暂不可用"},
+		// 被包装的 Error 同样按其错误码返回
+		{"包装的 Error", fmt.Errorf("create user: %w", apperror.New(409, apperror.EmailExists)), 409, 409, apperror.EmailExists},
+		{"非法错误码", apperror.New(200, "ok"), 500, 200, "ok"},
+		// 非 Error 类型不向客户端暴露原始错误信息
+		{"普通错误", errors.New("pq: connection refused"), 500, 500, apperror.InternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := record(t, func(c *gin.Context) { HandleError(c, tt.err) })
+			if status != tt.wantStatus || resp.Code != tt.wantCode || resp.Message != tt.wantMessage {
+				t.Errorf("status = %d, body = {code: %d, message: %q}, want %d {code: %d, message: %q}",
+					status, resp.Code, resp.Message, tt.wantStatus, tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}